@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jiazhoulvke/table2struct/generator"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+//compareFiles 将生成的代码与输出目录中已有的文件比较,返回不一致的文件
+//
+//showDiff为true时将差异以unified diff的格式输出到w
+func compareFiles(w io.Writer, files []generator.GeneratedFile, dir string, showDiff bool) ([]string, error) {
+	var changed []string
+	for _, file := range files {
+		path := filepath.Join(dir, file.Name)
+		existing, err := ioutil.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return changed, fmt.Errorf("读取文件%s失败:%v", path, err)
+		}
+		if err == nil && bytes.Equal(existing, file.Content) {
+			continue
+		}
+		changed = append(changed, path)
+		if !showDiff {
+			continue
+		}
+		fromFile := path
+		if os.IsNotExist(err) {
+			fromFile = "/dev/null"
+		}
+		if err := writeDiff(w, existing, file.Content, fromFile, path); err != nil {
+			return changed, err
+		}
+	}
+	return changed, nil
+}
+
+//staleFileSuffixes 与model一起生成的文件,model多余时它们也是多余的
+var staleFileSuffixes = []string{"_repo.go", "_proto.go", ".schema.json"}
+
+//compareStaleFiles 查找输出目录中本次没有生成的model,如已删除的表对应的文件,以及与它们一起生成的repo等文件
+//
+//包含可以还原为表结构的struct的go文件被认为是model。showDiff为true时将删除这些文件的diff输出到w
+func compareStaleFiles(w io.Writer, files []generator.GeneratedFile, dir string, showDiff bool) ([]string, error) {
+	generated := make(map[string]bool, len(files))
+	for _, file := range files {
+		generated[file.Name] = true
+	}
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("读取目录%s失败:%v", dir, err)
+	}
+	staleModels := make(map[string]bool)
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || generated[name] || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		if _, _, err := generator.ParseStructFiles([]string{filepath.Join(dir, name)}); err == nil {
+			staleModels[strings.TrimSuffix(name, ".go")] = true
+		}
+	}
+	var stale []string
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || generated[name] {
+			continue
+		}
+		isStale := staleModels[strings.TrimSuffix(name, ".go")] && strings.HasSuffix(name, ".go")
+		for _, suffix := range staleFileSuffixes {
+			if strings.HasSuffix(name, suffix) && staleModels[strings.TrimSuffix(name, suffix)] {
+				isStale = true
+			}
+		}
+		if !isStale {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		stale = append(stale, path)
+		if !showDiff {
+			continue
+		}
+		existing, err := ioutil.ReadFile(path)
+		if err != nil {
+			return stale, fmt.Errorf("读取文件%s失败:%v", path, err)
+		}
+		if err := writeDiff(w, existing, nil, path, "/dev/null"); err != nil {
+			return stale, err
+		}
+	}
+	return stale, nil
+}
+
+//writeDiff 将两个文件内容的差异以unified diff的格式输出到w
+func writeDiff(w io.Writer, a, b []byte, fromFile, toFile string) error {
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        diffLines(a),
+		B:        diffLines(b),
+		FromFile: fromFile,
+		ToFile:   toFile,
+		Context:  3,
+	})
+	if err != nil {
+		return fmt.Errorf("比较文件%s失败:%v", fromFile, err)
+	}
+	fmt.Fprint(w, diff)
+	return nil
+}
+
+//diffLines 将文件内容按行拆分,空文件没有任何行
+func diffLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+	return difflib.SplitLines(string(content))
+}
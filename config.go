@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/jiazhoulvke/table2struct/generator"
+	flag "github.com/spf13/pflag"
+	"gopkg.in/yaml.v2"
+)
+
+//configFileNames 未指定--config时在当前目录中自动查找的配置文件
+var configFileNames = []string{"table2struct.yaml", "table2struct.yml", "table2struct.toml"}
+
+//configFile 配置文件中无法用命令行参数表达的部分,其他配置项与命令行参数同名
+type configFile struct {
+	//Tables 只生成指定的表
+	Tables []string `yaml:"tables" toml:"tables"`
+	//Mappings 字段映射,键为表名(global表示所有表)和字段名
+	Mappings map[string]map[string]configMapping `yaml:"mappings" toml:"mappings"`
+	//TableOptions 针对单个表的设置
+	TableOptions map[string]configTableOptions `yaml:"table_options" toml:"table_options"`
+	//TypeRules 类型映射规则
+	TypeRules []configTypeRule `yaml:"type_rules" toml:"type_rules"`
+	//Imports 包名(别名)对应的导入路径
+	Imports map[string]string `yaml:"imports" toml:"imports"`
+}
+
+type configMapping struct {
+	Name string `yaml:"name" toml:"name"`
+	Type string `yaml:"type" toml:"type"`
+}
+
+type configTableOptions struct {
+	Skip       bool                     `yaml:"skip" toml:"skip"`
+	StructName string                   `yaml:"struct_name" toml:"struct_name"`
+	Comment    string                   `yaml:"comment" toml:"comment"`
+	Mappings   map[string]configMapping `yaml:"mappings" toml:"mappings"`
+}
+
+type configTypeRule struct {
+	DataType     string `yaml:"data_type" toml:"data_type"`
+	ColumnType   string `yaml:"column_type" toml:"column_type"`
+	Nullable     *bool  `yaml:"nullable" toml:"nullable"`
+	Unsigned     *bool  `yaml:"unsigned" toml:"unsigned"`
+	Column       string `yaml:"column" toml:"column"`
+	Table        string `yaml:"table" toml:"table"`
+	GoType       string `yaml:"go_type" toml:"go_type"`
+	Import       string `yaml:"import" toml:"import"`
+	NullableType string `yaml:"nullable_type" toml:"nullable_type"`
+}
+
+//findConfigFile 在当前目录中查找配置文件
+func findConfigFile() string {
+	for _, name := range configFileNames {
+		if _, err := os.Stat(name); err == nil {
+			return name
+		}
+	}
+	return ""
+}
+
+//loadConfig 读取配置文件,命令行中指定的参数优先于配置文件
+func loadConfig(path string) error {
+	if path == "" {
+		path = findConfigFile()
+		if path == "" {
+			return nil
+		}
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("读取配置文件失败:%v", err)
+	}
+	var values map[string]interface{}
+	var cfg configFile
+	if strings.ToLower(filepath.Ext(path)) == ".toml" {
+		if _, err = toml.Decode(string(content), &values); err == nil {
+			_, err = toml.Decode(string(content), &cfg)
+		}
+	} else {
+		if err = yaml.Unmarshal(content, &values); err == nil {
+			err = yaml.Unmarshal(content, &cfg)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("解析配置文件%s失败:%v", path, err)
+	}
+
+	for key, value := range values {
+		switch key {
+		case "tables", "mappings", "table_options", "type_rules", "imports":
+			continue
+		case "config", "query":
+			return fmt.Errorf("配置文件中不能设置%s", key)
+		}
+		f := flag.Lookup(key)
+		if f == nil {
+			return fmt.Errorf("未知的配置项:%s", key)
+		}
+		if f.Changed {
+			continue
+		}
+		if list, ok := value.([]interface{}); ok {
+			sliceValue, ok := f.Value.(flag.SliceValue)
+			if !ok {
+				return fmt.Errorf("配置项%s不能是列表", key)
+			}
+			items := make([]string, 0, len(list))
+			for _, item := range list {
+				items = append(items, fmt.Sprint(item))
+			}
+			if err := sliceValue.Replace(items); err != nil {
+				return fmt.Errorf("配置项%s错误:%v", key, err)
+			}
+			f.Changed = true
+			continue
+		}
+		if err := flag.Set(key, fmt.Sprint(value)); err != nil {
+			return fmt.Errorf("配置项%s错误:%v", key, err)
+		}
+	}
+
+	if len(opts.Tables) == 0 {
+		opts.Tables = cfg.Tables
+	}
+	if len(cfg.Mappings) > 0 {
+		opts.Mappings = make(map[string]map[string]generator.Mapping)
+		for tableName, mappings := range cfg.Mappings {
+			opts.Mappings[tableName] = toMappings(mappings)
+		}
+	}
+	if len(cfg.TableOptions) > 0 {
+		opts.TableOptions = make(map[string]generator.TableOptions)
+		for tableName, tableOptions := range cfg.TableOptions {
+			opts.TableOptions[tableName] = generator.TableOptions{
+				Skip:       tableOptions.Skip,
+				StructName: tableOptions.StructName,
+				Comment:    tableOptions.Comment,
+				Mappings:   toMappings(tableOptions.Mappings),
+			}
+		}
+	}
+	if len(cfg.Imports) > 0 {
+		//命令行中指定的导入路径优先
+		for alias, importPath := range opts.Imports {
+			cfg.Imports[alias] = importPath
+		}
+		opts.Imports = cfg.Imports
+	}
+	for _, rule := range cfg.TypeRules {
+		opts.TypeRules = append(opts.TypeRules, generator.TypeRule{
+			DataType:     rule.DataType,
+			ColumnType:   rule.ColumnType,
+			Nullable:     rule.Nullable,
+			Unsigned:     rule.Unsigned,
+			Column:       rule.Column,
+			Table:        rule.Table,
+			GoType:       rule.GoType,
+			Import:       rule.Import,
+			NullableType: rule.NullableType,
+		})
+	}
+	return nil
+}
+
+func toMappings(m map[string]configMapping) map[string]generator.Mapping {
+	mappings := make(map[string]generator.Mapping, len(m))
+	for columnName, mapping := range m {
+		mappings[columnName] = generator.Mapping{
+			FieldName: mapping.Name,
+			FieldType: mapping.Type,
+		}
+	}
+	return mappings
+}
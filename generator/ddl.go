@@ -0,0 +1,969 @@
+package generator
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+//DDLTable 从CREATE TABLE语句中解析出的表
+type DDLTable struct {
+	Schema      TableSchema
+	Columns     []ColumnSchema
+	ForeignKeys []ForeignKey
+	Indexes     []Index
+}
+
+//DDLSchema 从DDL文件中解析出的所有表,字段类型按MySQL规则转换
+type DDLSchema struct {
+	MySQLProvider
+	Tables []DDLTable
+}
+
+//GetTables 获取所有表,args不为空时只返回指定的表
+func (s *DDLSchema) GetTables(ctx context.Context, args []string) ([]TableSchema, error) {
+	tables := make([]TableSchema, 0, len(s.Tables))
+	for _, t := range s.Tables {
+		if len(args) > 0 && !inStrings(t.Schema.TableName, args) {
+			continue
+		}
+		tables = append(tables, t.Schema)
+	}
+	return tables, nil
+}
+
+//GetColumns 获取表的所有字段
+func (s *DDLSchema) GetColumns(ctx context.Context, tableSchema TableSchema) ([]ColumnSchema, error) {
+	for _, t := range s.Tables {
+		if t.Schema.TableName == tableSchema.TableName {
+			return t.Columns, nil
+		}
+	}
+	return nil, fmt.Errorf("表%s不存在", tableSchema.TableName)
+}
+
+//GetForeignKeys 获取所有外键
+func (s *DDLSchema) GetForeignKeys(ctx context.Context) ([]ForeignKey, error) {
+	var foreignKeys []ForeignKey
+	for _, t := range s.Tables {
+		foreignKeys = append(foreignKeys, t.ForeignKeys...)
+	}
+	return foreignKeys, nil
+}
+
+//GetIndexes 获取表的所有索引
+func (s *DDLSchema) GetIndexes(ctx context.Context, tableSchema TableSchema) ([]Index, error) {
+	for _, t := range s.Tables {
+		if t.Schema.TableName == tableSchema.TableName {
+			return append([]Index(nil), t.Indexes...), nil
+		}
+	}
+	return nil, fmt.Errorf("表%s不存在", tableSchema.TableName)
+}
+
+type ddlTokenKind int
+
+const (
+	ddlWord ddlTokenKind = iota
+	ddlIdent
+	ddlString
+	ddlPunct
+)
+
+type ddlToken struct {
+	kind  ddlTokenKind
+	text  string
+	start int
+	end   int
+}
+
+//is 判断token是否为指定的关键字或符号(不区分大小写)
+func (t ddlToken) is(s string) bool {
+	return (t.kind == ddlWord || t.kind == ddlPunct) && strings.EqualFold(t.text, s)
+}
+
+//tokenizeDDL 将SQL拆分为token,忽略注释
+func tokenizeDDL(src string) ([]ddlToken, error) {
+	tokens := make([]ddlToken, 0, 256)
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case unicode.IsSpace(rune(c)):
+			i++
+		case c == '#' || (c == '-' && strings.HasPrefix(src[i:], "-- ")) || (c == '-' && strings.HasPrefix(src[i:], "--\n")):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case c == '/' && strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("注释未结束")
+			}
+			i += end + 4
+		case c == '`' || c == '"':
+			start := i
+			var buf strings.Builder
+			i++
+			for {
+				if i >= len(src) {
+					return nil, fmt.Errorf("标识符未结束: %s", src[start:])
+				}
+				if src[i] == c {
+					if i+1 < len(src) && src[i+1] == c {
+						buf.WriteByte(c)
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				buf.WriteByte(src[i])
+				i++
+			}
+			tokens = append(tokens, ddlToken{kind: ddlIdent, text: buf.String(), start: start, end: i})
+		case c == '\'':
+			start := i
+			var buf strings.Builder
+			i++
+			for {
+				if i >= len(src) {
+					return nil, fmt.Errorf("字符串未结束: %s", src[start:])
+				}
+				if src[i] == '\\' && i+1 < len(src) {
+					buf.WriteByte(unescapeDDL(src[i+1]))
+					i += 2
+					continue
+				}
+				if src[i] == '\'' {
+					if i+1 < len(src) && src[i+1] == '\'' {
+						buf.WriteByte('\'')
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				buf.WriteByte(src[i])
+				i++
+			}
+			tokens = append(tokens, ddlToken{kind: ddlString, text: buf.String(), start: start, end: i})
+		case strings.IndexByte("(),;=", c) >= 0:
+			tokens = append(tokens, ddlToken{kind: ddlPunct, text: string(c), start: i, end: i + 1})
+			i++
+		default:
+			start := i
+			for i < len(src) && !unicode.IsSpace(rune(src[i])) && strings.IndexByte("(),;=`'\"", src[i]) < 0 {
+				i++
+			}
+			tokens = append(tokens, ddlToken{kind: ddlWord, text: src[start:i], start: start, end: i})
+		}
+	}
+	return tokens, nil
+}
+
+func unescapeDDL(c byte) byte {
+	switch c {
+	case 'n':
+		return '\n'
+	case 't':
+		return '\t'
+	case 'r':
+		return '\r'
+	case '0':
+		return 0
+	}
+	return c
+}
+
+type ddlParser struct {
+	src    string
+	tokens []ddlToken
+	pos    int
+}
+
+func (p *ddlParser) eof() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *ddlParser) peek() ddlToken {
+	if p.eof() {
+		return ddlToken{kind: ddlPunct}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *ddlParser) next() ddlToken {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+//accept 当下一组token依次匹配时消费它们
+func (p *ddlParser) accept(words ...string) bool {
+	for i, w := range words {
+		if p.pos+i >= len(p.tokens) || !p.tokens[p.pos+i].is(w) {
+			return false
+		}
+	}
+	p.pos += len(words)
+	return true
+}
+
+//skipGroup 跳过一个括号组,返回括号内的原始文本
+func (p *ddlParser) skipGroup() (string, error) {
+	open := p.next()
+	if !open.is("(") {
+		return "", fmt.Errorf("期望'(',实际为'%s'", open.text)
+	}
+	depth := 1
+	for !p.eof() {
+		t := p.next()
+		if t.is("(") {
+			depth++
+		} else if t.is(")") {
+			depth--
+			if depth == 0 {
+				return p.src[open.end:t.start], nil
+			}
+		}
+	}
+	return "", fmt.Errorf("括号未闭合")
+}
+
+//skipStatement 跳到下一个分号之后
+func (p *ddlParser) skipStatement() {
+	for !p.eof() {
+		if p.next().is(";") {
+			return
+		}
+	}
+}
+
+//ParseDDL 解析MySQL的CREATE TABLE语句,其他语句会被忽略
+func ParseDDL(src string) (*DDLSchema, error) {
+	tokens, err := tokenizeDDL(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &ddlParser{src: src, tokens: tokens}
+	schema := &DDLSchema{}
+	for !p.eof() {
+		if !p.accept("CREATE") {
+			p.skipStatement()
+			continue
+		}
+		if t := p.peek(); t.is("UNIQUE") || t.is("FULLTEXT") || t.is("SPATIAL") || t.is("INDEX") {
+			if err := p.parseCreateIndex(schema); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		p.accept("TEMPORARY")
+		if !p.accept("TABLE") {
+			p.skipStatement()
+			continue
+		}
+		p.accept("IF", "NOT", "EXISTS")
+		table, err := p.parseCreateTable()
+		if err != nil {
+			return nil, err
+		}
+		if table != nil {
+			schema.Tables = append(schema.Tables, *table)
+		}
+	}
+	return schema, nil
+}
+
+//parseTableName 解析表名,支持db.table形式
+func (p *ddlParser) parseTableName() (schema, name string, err error) {
+	t := p.next()
+	if t.kind != ddlIdent && t.kind != ddlWord {
+		return "", "", fmt.Errorf("无法解析表名: %s", t.text)
+	}
+	name = t.text
+	if p.peek().kind == ddlWord && strings.HasPrefix(p.peek().text, ".") {
+		schema = t.text
+		name = strings.TrimPrefix(p.next().text, ".")
+		if name == "" {
+			name = p.next().text
+		}
+	} else if t.kind == ddlWord && strings.Contains(t.text, ".") {
+		parts := strings.SplitN(t.text, ".", 2)
+		schema, name = parts[0], parts[1]
+		if name == "" {
+			name = p.next().text
+		}
+	}
+	return schema, name, nil
+}
+
+func (p *ddlParser) parseCreateTable() (*DDLTable, error) {
+	table := &DDLTable{}
+	var err error
+	table.Schema.TableSchema, table.Schema.TableName, err = p.parseTableName()
+	if err != nil {
+		return nil, err
+	}
+	table.Schema.TableType = "BASE TABLE"
+	//CREATE TABLE a LIKE b 无法得到字段信息
+	if !p.peek().is("(") {
+		p.skipStatement()
+		return nil, nil
+	}
+	p.next()
+	var keys []ddlKey
+	for {
+		if p.eof() {
+			return nil, fmt.Errorf("表%s的定义未结束", table.Schema.TableName)
+		}
+		if p.accept(")") {
+			break
+		}
+		if p.accept(",") {
+			continue
+		}
+		t := p.peek()
+		if t.kind == ddlWord && isDDLKeyDefinition(t.text) {
+			key, err := p.parseKey()
+			if err != nil {
+				return nil, fmt.Errorf("解析表%s的索引失败: %v", table.Schema.TableName, err)
+			}
+			keys = append(keys, key)
+			continue
+		}
+		col, err := p.parseColumn()
+		if err != nil {
+			return nil, fmt.Errorf("解析表%s的字段失败: %v", table.Schema.TableName, err)
+		}
+		col.TableSchema = table.Schema.TableSchema
+		col.TableName = table.Schema.TableName
+		col.OrdinalPosition = sql.NullInt64{Int64: int64(len(table.Columns) + 1), Valid: true}
+		table.Columns = append(table.Columns, col)
+	}
+	p.parseTableOptions(&table.Schema)
+	applyDDLKeys(table, append(inlineDDLKeys(table.Columns), keys...))
+	return table, nil
+}
+
+//ddlKey 表定义中的索引
+type ddlKey struct {
+	Kind       string
+	Name       string
+	Type       string
+	Columns    []string
+	SubParts   []int
+	RefTable   string
+	RefColumns []string
+	OnUpdate   string
+	OnDelete   string
+}
+
+func isDDLKeyDefinition(word string) bool {
+	switch strings.ToUpper(word) {
+	case "PRIMARY", "KEY", "INDEX", "UNIQUE", "CONSTRAINT", "FOREIGN", "FULLTEXT", "SPATIAL", "CHECK":
+		return true
+	}
+	return false
+}
+
+//parseCreateIndex 解析CREATE INDEX语句,并将索引添加到之前定义的表中
+func (p *ddlParser) parseCreateIndex(schema *DDLSchema) error {
+	key := ddlKey{Kind: "MUL"}
+	switch {
+	case p.accept("UNIQUE"):
+		key.Kind = "UNI"
+	case p.accept("FULLTEXT"):
+		key.Type = "FULLTEXT"
+	case p.accept("SPATIAL"):
+		key.Type = "SPATIAL"
+	}
+	if !p.accept("INDEX") {
+		p.skipStatement()
+		return nil
+	}
+	p.accept("CONCURRENTLY")
+	p.accept("IF", "NOT", "EXISTS")
+	if !p.peek().is("ON") {
+		key.Name = p.next().text
+	}
+	//跳过USING BTREE等,直到ON
+	for !p.eof() && !p.peek().is("ON") && !p.peek().is(";") {
+		p.next()
+	}
+	if !p.accept("ON") {
+		p.skipStatement()
+		return nil
+	}
+	p.accept("ONLY")
+	_, tableName, err := p.parseTableName()
+	if err != nil {
+		return err
+	}
+	for !p.eof() && !p.peek().is("(") && !p.peek().is(";") {
+		p.next()
+	}
+	if !p.peek().is("(") {
+		p.skipStatement()
+		return nil
+	}
+	group, err := p.skipGroup()
+	if err != nil {
+		return err
+	}
+	key.Columns, key.SubParts = parseKeyColumns(group)
+	p.skipStatement()
+	for i := range schema.Tables {
+		if schema.Tables[i].Schema.TableName == tableName {
+			applyDDLKeys(&schema.Tables[i], []ddlKey{key})
+			break
+		}
+	}
+	return nil
+}
+
+func (p *ddlParser) parseKey() (ddlKey, error) {
+	var key ddlKey
+	if p.accept("CONSTRAINT") {
+		if t := p.peek(); !t.is("PRIMARY") && !t.is("UNIQUE") && !t.is("FOREIGN") && !t.is("CHECK") {
+			key.Name = p.next().text
+		}
+	}
+	switch {
+	case p.accept("PRIMARY", "KEY"):
+		key.Kind = "PRI"
+	case p.accept("UNIQUE"):
+		key.Kind = "UNI"
+	case p.accept("FOREIGN", "KEY"):
+		key.Kind = "FOREIGN"
+	case p.accept("CHECK"):
+		key.Kind = "CHECK"
+	default:
+		if p.accept("FULLTEXT") {
+			key.Type = "FULLTEXT"
+		} else if p.accept("SPATIAL") {
+			key.Type = "SPATIAL"
+		}
+		key.Kind = "MUL"
+	}
+	//跳过索引名、索引类型等,直到字段列表
+	for !p.eof() && !p.peek().is("(") {
+		if p.peek().is(",") || p.peek().is(")") {
+			return key, nil
+		}
+		t := p.next()
+		if t.kind == ddlIdent || (t.kind == ddlWord && !t.is("KEY") && !t.is("INDEX") && !t.is("USING") && !t.is("BTREE") && !t.is("HASH")) {
+			key.Name = t.text
+		}
+	}
+	group, err := p.skipGroup()
+	if err != nil {
+		return key, err
+	}
+	if key.Kind != "CHECK" {
+		key.Columns, key.SubParts = parseKeyColumns(group)
+	}
+	//跳过USING等剩余部分
+	for !p.eof() && !p.peek().is(",") && !p.peek().is(")") {
+		if key.Kind == "FOREIGN" && p.accept("REFERENCES") {
+			key.RefTable = p.next().text
+			if p.peek().kind == ddlWord && strings.HasPrefix(p.peek().text, ".") {
+				key.RefTable = strings.TrimPrefix(p.next().text, ".")
+				if key.RefTable == "" {
+					key.RefTable = p.next().text
+				}
+			} else if i := strings.LastIndex(key.RefTable, "."); i >= 0 {
+				key.RefTable = key.RefTable[i+1:]
+			}
+			if p.peek().is("(") {
+				group, err := p.skipGroup()
+				if err != nil {
+					return key, err
+				}
+				key.RefColumns, _ = parseKeyColumns(group)
+			}
+			continue
+		}
+		if key.Kind == "FOREIGN" && p.accept("ON") {
+			event := strings.ToUpper(p.next().text)
+			action := strings.ToUpper(p.next().text)
+			if action == "SET" || action == "NO" {
+				action += " " + strings.ToUpper(p.next().text)
+			}
+			if event == "UPDATE" {
+				key.OnUpdate = action
+			} else {
+				key.OnDelete = action
+			}
+			continue
+		}
+		if p.peek().is("(") {
+			if _, err := p.skipGroup(); err != nil {
+				return key, err
+			}
+			continue
+		}
+		p.next()
+	}
+	return key, nil
+}
+
+//parseKeyColumns 解析索引字段列表,如 `a`,`b`(10) DESC,返回字段名和前缀索引的长度
+func parseKeyColumns(group string) (columns []string, subParts []int) {
+	tokens, err := tokenizeDDL(group)
+	if err != nil {
+		return nil, nil
+	}
+	depth := 0
+	expectName := true
+	for i, t := range tokens {
+		switch {
+		case t.is("("):
+			//字段名后面的(10)为前缀长度
+			if depth == 0 && !expectName && i+2 < len(tokens) && tokens[i+2].is(")") {
+				if n, err := strconv.Atoi(tokens[i+1].text); err == nil {
+					subParts[len(subParts)-1] = n
+				}
+			}
+			depth++
+		case t.is(")"):
+			depth--
+		case t.is(",") && depth == 0:
+			expectName = true
+		case expectName && depth == 0 && (t.kind == ddlIdent || t.kind == ddlWord):
+			columns = append(columns, t.text)
+			subParts = append(subParts, 0)
+			expectName = false
+		}
+	}
+	return columns, subParts
+}
+
+//inlineDDLKeys 将字段定义中的PRIMARY KEY和UNIQUE转换为索引定义
+func inlineDDLKeys(columns []ColumnSchema) []ddlKey {
+	var keys []ddlKey
+	primary := ddlKey{Kind: "PRI"}
+	for _, col := range columns {
+		switch col.ColumnKey.String {
+		case "PRI":
+			primary.Columns = append(primary.Columns, col.ColumnName)
+			primary.SubParts = append(primary.SubParts, 0)
+		case "UNI":
+			keys = append(keys, ddlKey{Kind: "UNI", Columns: []string{col.ColumnName}, SubParts: []int{0}})
+		}
+	}
+	if len(primary.Columns) > 0 {
+		keys = append([]ddlKey{primary}, keys...)
+	}
+	return keys
+}
+
+func applyDDLKeys(table *DDLTable, keys []ddlKey) {
+	var foreignKeys []ddlKey
+	for _, key := range keys {
+		if key.Kind == "FOREIGN" && key.RefTable != "" {
+			foreignKey := ForeignKey{
+				Name:         key.Name,
+				TableName:    table.Schema.TableName,
+				Columns:      key.Columns,
+				RefTableName: key.RefTable,
+				RefColumns:   key.RefColumns,
+				OnUpdate:     key.OnUpdate,
+				OnDelete:     key.OnDelete,
+			}
+			if foreignKey.Name == "" {
+				foreignKey.Name = fmt.Sprintf("%s_ibfk_%d", table.Schema.TableName, len(table.ForeignKeys)+1)
+			}
+			table.ForeignKeys = append(table.ForeignKeys, foreignKey)
+			foreignKeys = append(foreignKeys, key)
+			//外键会自动创建索引
+			key.Kind = "MUL"
+		} else {
+			addDDLIndex(table, key)
+		}
+		if key.Kind != "PRI" && key.Kind != "UNI" && key.Kind != "MUL" {
+			continue
+		}
+		for i, name := range key.Columns {
+			for j := range table.Columns {
+				col := &table.Columns[j]
+				if !strings.EqualFold(col.ColumnName, name) {
+					continue
+				}
+				switch key.Kind {
+				case "PRI":
+					col.ColumnKey = sql.NullString{String: "PRI", Valid: true}
+					col.IsNullAble = "NO"
+				case "UNI":
+					//联合唯一索引在information_schema中标记为MUL
+					if col.ColumnKey.String == "" {
+						if len(key.Columns) == 1 {
+							col.ColumnKey = sql.NullString{String: "UNI", Valid: true}
+						} else if i == 0 {
+							col.ColumnKey = sql.NullString{String: "MUL", Valid: true}
+						}
+					}
+				case "MUL":
+					if col.ColumnKey.String == "" && i == 0 {
+						col.ColumnKey = sql.NullString{String: "MUL", Valid: true}
+					}
+				}
+			}
+		}
+	}
+	//已有以外键字段开头的索引时MySQL不会为外键另外创建索引
+	for _, key := range foreignKeys {
+		covered := false
+		for _, index := range table.Indexes {
+			if hasColumnPrefix(index.ColumnNames(), key.Columns) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			key.Kind = "MUL"
+			addDDLIndex(table, key)
+		}
+	}
+}
+
+//addDDLIndex 将索引定义添加到表中,未命名的索引按MySQL的规则以第一个字段命名
+func addDDLIndex(table *DDLTable, key ddlKey) {
+	if len(key.Columns) == 0 || (key.Kind != "PRI" && key.Kind != "UNI" && key.Kind != "MUL") {
+		return
+	}
+	index := Index{
+		Name:      key.Name,
+		TableName: table.Schema.TableName,
+		IsPrimary: key.Kind == "PRI",
+		IsUnique:  key.Kind == "PRI" || key.Kind == "UNI",
+		Type:      key.Type,
+	}
+	if index.Type == "" {
+		index.Type = "BTREE"
+	}
+	if index.IsPrimary {
+		index.Name = "PRIMARY"
+		//字段定义和表定义中都声明了主键时只保留一个
+		for _, existing := range table.Indexes {
+			if existing.IsPrimary {
+				return
+			}
+		}
+	}
+	if index.Name == "" {
+		index.Name = key.Columns[0]
+		for i := 2; ddlIndexExists(table.Indexes, index.Name); i++ {
+			index.Name = key.Columns[0] + "_" + strconv.Itoa(i)
+		}
+	}
+	for i, name := range key.Columns {
+		column := IndexColumn{Name: name}
+		if i < len(key.SubParts) {
+			column.SubPart = key.SubParts[i]
+		}
+		index.Columns = append(index.Columns, column)
+	}
+	table.Indexes = append(table.Indexes, index)
+}
+
+func ddlIndexExists(indexes []Index, name string) bool {
+	for _, index := range indexes {
+		if strings.EqualFold(index.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+//hasColumnPrefix 判断columns是否以prefix开头
+func hasColumnPrefix(columns, prefix []string) bool {
+	if len(columns) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if !strings.EqualFold(columns[i], prefix[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *ddlParser) parseColumn() (ColumnSchema, error) {
+	var col ColumnSchema
+	name := p.next()
+	if name.kind != ddlIdent && name.kind != ddlWord {
+		return col, fmt.Errorf("无法解析字段名: %s", name.text)
+	}
+	col.ColumnName = name.text
+	typeToken := p.next()
+	if typeToken.kind != ddlWord {
+		return col, fmt.Errorf("无法解析字段%s的类型", col.ColumnName)
+	}
+	col.DataType = strings.ToLower(typeToken.text)
+	columnType := col.DataType
+	var typeArgs string
+	if p.peek().is("(") {
+		group, err := p.skipGroup()
+		if err != nil {
+			return col, err
+		}
+		typeArgs = group
+		columnType += "(" + group + ")"
+	}
+	for {
+		if p.accept("UNSIGNED") {
+			columnType += " unsigned"
+		} else if p.accept("ZEROFILL") {
+			columnType += " zerofill"
+		} else if !p.accept("SIGNED") {
+			//SIGNED是默认值,不需要写入类型
+			break
+		}
+	}
+	switch col.DataType {
+	case "boolean", "bool":
+		col.DataType = "tinyint"
+		columnType = "tinyint(1)"
+	default:
+		//与information_schema一致,使用类型的标准名称
+		if dataType, ok := ddlTypeAliases[col.DataType]; ok {
+			columnType = dataType + columnType[len(col.DataType):]
+			col.DataType = dataType
+		}
+	}
+	col.ColumnType = columnType
+	fillDDLTypeLength(&col, typeArgs)
+
+	col.IsNullAble = "YES"
+	var extras []string
+	for !p.eof() && !p.peek().is(",") && !p.peek().is(")") {
+		switch {
+		case p.accept("NOT", "NULL"):
+			col.IsNullAble = "NO"
+		case p.accept("NULL"):
+			col.IsNullAble = "YES"
+		case p.accept("DEFAULT"):
+			value, err := p.parseDefault()
+			if err != nil {
+				return col, err
+			}
+			col.ColumnDefault = value
+		case p.accept("AUTO_INCREMENT"):
+			extras = append(extras, "auto_increment")
+		case p.accept("PRIMARY", "KEY"), p.accept("KEY"):
+			col.ColumnKey = sql.NullString{String: "PRI", Valid: true}
+			col.IsNullAble = "NO"
+		case p.accept("UNIQUE"):
+			p.accept("KEY")
+			if col.ColumnKey.String == "" {
+				col.ColumnKey = sql.NullString{String: "UNI", Valid: true}
+			}
+		case p.accept("COMMENT"):
+			t := p.next()
+			col.ColumnComment = sql.NullString{String: t.text, Valid: true}
+		case p.accept("CHARACTER", "SET"), p.accept("CHARSET"):
+			col.CharacterSetName = sql.NullString{String: p.next().text, Valid: true}
+		case p.accept("COLLATE"):
+			col.CollationName = sql.NullString{String: p.next().text, Valid: true}
+		case p.accept("ON", "UPDATE"):
+			t := p.next()
+			expr := strings.ToUpper(t.text)
+			if p.peek().is("(") {
+				group, err := p.skipGroup()
+				if err != nil {
+					return col, err
+				}
+				expr += "(" + group + ")"
+			}
+			extras = append(extras, "on update "+expr)
+		case p.accept("GENERATED", "ALWAYS", "AS"), p.accept("AS"):
+			expr, err := p.skipGroup()
+			if err != nil {
+				return col, err
+			}
+			col.GenerationExpression = expr
+			if p.accept("STORED") {
+				extras = append(extras, "STORED GENERATED")
+			} else {
+				p.accept("VIRTUAL")
+				extras = append(extras, "VIRTUAL GENERATED")
+			}
+		default:
+			//忽略其他不影响结构生成的属性,如COLUMN_FORMAT、REFERENCES、CHECK等
+			if p.peek().is("(") {
+				if _, err := p.skipGroup(); err != nil {
+					return col, err
+				}
+				continue
+			}
+			p.next()
+		}
+	}
+	if len(extras) > 0 {
+		col.Extra = sql.NullString{String: strings.Join(extras, " "), Valid: true}
+	}
+	return col, nil
+}
+
+//ddlTypeAliases MySQL中类型的别名对应的标准名称
+var ddlTypeAliases = map[string]string{
+	"integer":   "int",
+	"int1":      "tinyint",
+	"int2":      "smallint",
+	"int3":      "mediumint",
+	"middleint": "mediumint",
+	"int4":      "int",
+	"int8":      "bigint",
+	"dec":       "decimal",
+	"fixed":     "decimal",
+	"numeric":   "decimal",
+	"nchar":     "char",
+	"nvarchar":  "varchar",
+}
+
+//parseDefault 解析默认值,DEFAULT NULL返回无效值
+func (p *ddlParser) parseDefault() (sql.NullString, error) {
+	t := p.next()
+	//紧跟字符串的前缀,如b'0'、x'FF'、_utf8mb4'abc'、N'abc'
+	if s := p.peek(); t.kind == ddlWord && s.kind == ddlString && s.start == t.end {
+		p.pos++
+		switch prefix := strings.ToLower(t.text); prefix {
+		case "b", "x":
+			return sql.NullString{String: prefix + "'" + s.text + "'", Valid: true}, nil
+		}
+		return sql.NullString{String: s.text, Valid: true}, nil
+	}
+	switch {
+	case t.kind == ddlString:
+		return sql.NullString{String: t.text, Valid: true}, nil
+	case t.is("NULL"):
+		return sql.NullString{}, nil
+	case t.is("("):
+		p.pos--
+		group, err := p.skipGroup()
+		if err != nil {
+			return sql.NullString{}, err
+		}
+		return sql.NullString{String: group, Valid: true}, nil
+	}
+	value := t.text
+	//CURRENT_TIMESTAMP(3)之类的函数
+	if p.peek().is("(") {
+		group, err := p.skipGroup()
+		if err != nil {
+			return sql.NullString{}, err
+		}
+		value += "(" + group + ")"
+	}
+	if t.kind == ddlWord && !isDDLNumber(value) {
+		value = strings.ToUpper(value)
+	}
+	return sql.NullString{String: value, Valid: true}, nil
+}
+
+func isDDLNumber(s string) bool {
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil
+}
+
+//ddlTypeLengths 没有指定长度时字符串和二进制类型的最大长度,与information_schema中的CHARACTER_MAXIMUM_LENGTH一致
+var ddlTypeLengths = map[string]int64{
+	"char":       1,
+	"binary":     1,
+	"tinytext":   255,
+	"tinyblob":   255,
+	"text":       65535,
+	"blob":       65535,
+	"mediumtext": 16777215,
+	"mediumblob": 16777215,
+	"longtext":   4294967295,
+	"longblob":   4294967295,
+}
+
+//ddlIntegerPrecisions 整型在information_schema中的NUMERIC_PRECISION,与显示宽度无关,第二个值为unsigned时的精度
+var ddlIntegerPrecisions = map[string][2]int64{
+	"tinyint":   {3, 3},
+	"smallint":  {5, 5},
+	"mediumint": {7, 8},
+	"int":       {10, 10},
+	"bigint":    {19, 20},
+}
+
+//fillDDLTypeLength 根据类型参数填充长度、精度等信息
+func fillDDLTypeLength(col *ColumnSchema, typeArgs string) {
+	args := strings.Split(typeArgs, ",")
+	first, _ := strconv.ParseInt(strings.TrimSpace(args[0]), 10, 64)
+	switch col.DataType {
+	case "char", "varchar", "binary", "varbinary", "tinytext", "text", "mediumtext", "longtext", "tinyblob", "blob", "mediumblob", "longblob":
+		if first <= 0 {
+			first = ddlTypeLengths[col.DataType]
+		}
+		if first > 0 {
+			col.CharacterMaximumLength = sql.NullInt64{Int64: first, Valid: true}
+		}
+	case "tinyint", "smallint", "mediumint", "int", "bigint":
+		precision := ddlIntegerPrecisions[col.DataType][0]
+		if strings.Contains(col.ColumnType, "unsigned") {
+			precision = ddlIntegerPrecisions[col.DataType][1]
+		}
+		col.NumericPrecision = sql.NullInt64{Int64: precision, Valid: true}
+		col.NumericScale = sql.NullInt64{Int64: 0, Valid: true}
+	case "decimal", "numeric", "float", "double":
+		if first > 0 {
+			col.NumericPrecision = sql.NullInt64{Int64: first, Valid: true}
+		}
+		if len(args) > 1 {
+			scale, _ := strconv.ParseInt(strings.TrimSpace(args[1]), 10, 64)
+			col.NumericScale = sql.NullInt64{Int64: scale, Valid: true}
+		} else if col.DataType == "decimal" || col.DataType == "numeric" {
+			col.NumericScale = sql.NullInt64{Int64: 0, Valid: true}
+		}
+	case "datetime", "timestamp", "time":
+		col.DatetimePrecision = sql.NullInt64{Int64: first, Valid: true}
+	}
+}
+
+func (p *ddlParser) parseTableOptions(schema *TableSchema) {
+	for !p.eof() {
+		if p.accept(";") {
+			return
+		}
+		switch {
+		case p.accept("ENGINE"):
+			p.accept("=")
+			schema.Engine = p.next().text
+		case p.accept("COMMENT"):
+			p.accept("=")
+			schema.TableComment = sql.NullString{String: p.next().text, Valid: true}
+		case p.accept("AUTO_INCREMENT"):
+			p.accept("=")
+			if n, err := strconv.ParseInt(p.next().text, 10, 64); err == nil {
+				schema.AutoIncrement = sql.NullInt64{Int64: n, Valid: true}
+			}
+		case p.accept("COLLATE"):
+			p.accept("=")
+			schema.TableCollation = sql.NullString{String: p.next().text, Valid: true}
+		case p.accept("ROW_FORMAT"):
+			p.accept("=")
+			schema.RowFormat = sql.NullString{String: strings.ToUpper(p.next().text), Valid: true}
+		case p.peek().is("("):
+			p.skipGroup()
+		default:
+			p.next()
+		}
+	}
+}
+
+func inStrings(s string, list []string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,314 @@
+package generator
+
+import (
+	"context"
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestTokenizeDDL(t *testing.T) {
+	tests := []struct {
+		src  string
+		want []ddlToken
+	}{
+		{
+			src: "CREATE TABLE `a``b` (id int);",
+			want: []ddlToken{
+				{kind: ddlWord, text: "CREATE"}, {kind: ddlWord, text: "TABLE"}, {kind: ddlIdent, text: "a`b"},
+				{kind: ddlPunct, text: "("}, {kind: ddlWord, text: "id"}, {kind: ddlWord, text: "int"},
+				{kind: ddlPunct, text: ")"}, {kind: ddlPunct, text: ";"},
+			},
+		},
+		{
+			src:  `"x""y" 'it''s' 'a\'b\n'`,
+			want: []ddlToken{{kind: ddlIdent, text: `x"y`}, {kind: ddlString, text: "it's"}, {kind: ddlString, text: "a'b\n"}},
+		},
+		{
+			src:  "a # comment\nb -- comment\nc /* comment */ d--e",
+			want: []ddlToken{{kind: ddlWord, text: "a"}, {kind: ddlWord, text: "b"}, {kind: ddlWord, text: "c"}, {kind: ddlWord, text: "d--e"}},
+		},
+		{
+			src:  "DEFAULT b'0' COMMENT='x'",
+			want: []ddlToken{{kind: ddlWord, text: "DEFAULT"}, {kind: ddlWord, text: "b"}, {kind: ddlString, text: "0"}, {kind: ddlWord, text: "COMMENT"}, {kind: ddlPunct, text: "="}, {kind: ddlString, text: "x"}},
+		},
+	}
+	for _, test := range tests {
+		tokens, err := tokenizeDDL(test.src)
+		if err != nil {
+			t.Errorf("%q: %v", test.src, err)
+			continue
+		}
+		for i := range tokens {
+			tokens[i].start, tokens[i].end = 0, 0
+		}
+		if !reflect.DeepEqual(tokens, test.want) {
+			t.Errorf("%q:\n got %v\nwant %v", test.src, tokens, test.want)
+		}
+	}
+}
+
+func TestTokenizeDDLError(t *testing.T) {
+	for _, src := range []string{"`abc", `"abc`, "'abc", "/* abc"} {
+		if _, err := tokenizeDDL(src); err == nil {
+			t.Errorf("%q: 期望返回错误", src)
+		}
+	}
+}
+
+func TestParseDDLColumn(t *testing.T) {
+	valid := func(s string) sql.NullString { return sql.NullString{String: s, Valid: true} }
+	length := func(n int64) sql.NullInt64 { return sql.NullInt64{Int64: n, Valid: true} }
+	tests := []struct {
+		definition string
+		want       ColumnSchema
+	}{
+		{
+			definition: "id integer(11) unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY",
+			want: ColumnSchema{DataType: "int", ColumnType: "int(11) unsigned", IsNullAble: "NO", ColumnKey: valid("PRI"), Extra: valid("auto_increment"),
+				NumericPrecision: length(10), NumericScale: length(0)},
+		},
+		{
+			definition: "total bigint unsigned zerofill NOT NULL",
+			want:       ColumnSchema{DataType: "bigint", ColumnType: "bigint unsigned zerofill", IsNullAble: "NO", NumericPrecision: length(20), NumericScale: length(0)},
+		},
+		{
+			definition: "delta smallint(6) SIGNED",
+			want:       ColumnSchema{DataType: "smallint", ColumnType: "smallint(6)", IsNullAble: "YES", NumericPrecision: length(5), NumericScale: length(0)},
+		},
+		{
+			definition: "flag bit(1) NOT NULL DEFAULT b'0'",
+			want:       ColumnSchema{DataType: "bit", ColumnType: "bit(1)", IsNullAble: "NO", ColumnDefault: valid("b'0'")},
+		},
+		{
+			definition: "hash varbinary(4) DEFAULT X'FF'",
+			want:       ColumnSchema{DataType: "varbinary", ColumnType: "varbinary(4)", IsNullAble: "YES", ColumnDefault: valid("x'FF'"), CharacterMaximumLength: length(4)},
+		},
+		{
+			definition: "name varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin DEFAULT _utf8mb4'abc' COMMENT 'it''s'",
+			want: ColumnSchema{DataType: "varchar", ColumnType: "varchar(50)", IsNullAble: "YES", ColumnDefault: valid("abc"), CharacterMaximumLength: length(50),
+				CharacterSetName: valid("utf8mb4"), CollationName: valid("utf8mb4_bin"), ColumnComment: valid("it's")},
+		},
+		{
+			definition: "code char NOT NULL",
+			want:       ColumnSchema{DataType: "char", ColumnType: "char", IsNullAble: "NO", CharacterMaximumLength: length(1)},
+		},
+		{
+			definition: "body mediumtext",
+			want:       ColumnSchema{DataType: "mediumtext", ColumnType: "mediumtext", IsNullAble: "YES", CharacterMaximumLength: length(16777215)},
+		},
+		{
+			definition: "data blob",
+			want:       ColumnSchema{DataType: "blob", ColumnType: "blob", IsNullAble: "YES", CharacterMaximumLength: length(65535)},
+		},
+		{
+			definition: "price numeric(10,2) NOT NULL DEFAULT 0.00",
+			want:       ColumnSchema{DataType: "decimal", ColumnType: "decimal(10,2)", IsNullAble: "NO", ColumnDefault: valid("0.00"), NumericPrecision: length(10), NumericScale: length(2)},
+		},
+		{
+			definition: "amount dec(8)",
+			want:       ColumnSchema{DataType: "decimal", ColumnType: "decimal(8)", IsNullAble: "YES", NumericPrecision: length(8), NumericScale: length(0)},
+		},
+		{
+			definition: "level middleint",
+			want:       ColumnSchema{DataType: "mediumint", ColumnType: "mediumint", IsNullAble: "YES", NumericPrecision: length(7), NumericScale: length(0)},
+		},
+		{
+			definition: "enabled boolean NOT NULL DEFAULT true",
+			want:       ColumnSchema{DataType: "tinyint", ColumnType: "tinyint(1)", IsNullAble: "NO", ColumnDefault: valid("TRUE"), NumericPrecision: length(3), NumericScale: length(0)},
+		},
+		{
+			definition: "updated_at datetime(3) DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE current_timestamp(3)",
+			want: ColumnSchema{DataType: "datetime", ColumnType: "datetime(3)", IsNullAble: "YES", ColumnDefault: valid("CURRENT_TIMESTAMP(3)"),
+				DatetimePrecision: length(3), Extra: valid("on update CURRENT_TIMESTAMP(3)")},
+		},
+		{
+			definition: "total int GENERATED ALWAYS AS (price * 2) STORED",
+			want: ColumnSchema{DataType: "int", ColumnType: "int", IsNullAble: "YES", GenerationExpression: "price * 2", Extra: valid("STORED GENERATED"),
+				NumericPrecision: length(10), NumericScale: length(0)},
+		},
+		{
+			definition: "status enum('on','off') NOT NULL DEFAULT 'on'",
+			want:       ColumnSchema{DataType: "enum", ColumnType: "enum('on','off')", IsNullAble: "NO", ColumnDefault: valid("on")},
+		},
+	}
+	for _, test := range tests {
+		schema, err := ParseDDL("CREATE TABLE t (" + test.definition + ");")
+		if err != nil {
+			t.Errorf("%s: %v", test.definition, err)
+			continue
+		}
+		got := schema.Tables[0].Columns[0]
+		want := test.want
+		want.TableName = "t"
+		want.ColumnName = strings.Fields(test.definition)[0]
+		want.OrdinalPosition = length(1)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s:\n got %+v\nwant %+v", test.definition, got, want)
+		}
+	}
+}
+
+func TestParseDDLTable(t *testing.T) {
+	schema, err := ParseDDL(`
+-- 忽略其他语句
+SET NAMES utf8mb4;
+DROP TABLE IF EXISTS shop.item;
+CREATE TABLE IF NOT EXISTS shop.item (
+  id bigint NOT NULL,
+  team_id int DEFAULT NULL,
+  name varchar(255) NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY uk_name (name(10)),
+  CONSTRAINT fk_item_team FOREIGN KEY (team_id) REFERENCES team (id) ON DELETE CASCADE,
+  CHECK (id > 0)
+) ENGINE=InnoDB AUTO_INCREMENT=10 COLLATE=utf8mb4_bin COMMENT='商品';
+CREATE INDEX idx_team ON shop.item (team_id);
+CREATE TABLE copy LIKE item;
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schema.Tables) != 1 {
+		t.Fatalf("期望1张表,实际为%d", len(schema.Tables))
+	}
+	table := schema.Tables[0]
+	wantSchema := TableSchema{
+		TableSchema:    "shop",
+		TableName:      "item",
+		TableType:      "BASE TABLE",
+		Engine:         "InnoDB",
+		AutoIncrement:  sql.NullInt64{Int64: 10, Valid: true},
+		TableCollation: sql.NullString{String: "utf8mb4_bin", Valid: true},
+		TableComment:   sql.NullString{String: "商品", Valid: true},
+	}
+	if !reflect.DeepEqual(table.Schema, wantSchema) {
+		t.Errorf("表:\n got %+v\nwant %+v", table.Schema, wantSchema)
+	}
+	keys := make(map[string]string, len(table.Columns))
+	for _, col := range table.Columns {
+		keys[col.ColumnName] = col.ColumnKey.String
+	}
+	if want := map[string]string{"id": "PRI", "team_id": "MUL", "name": "UNI"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("COLUMN_KEY: got %v, want %v", keys, want)
+	}
+	wantIndexes := []Index{
+		{Name: "PRIMARY", TableName: "item", IsPrimary: true, IsUnique: true, Type: "BTREE", Columns: []IndexColumn{{Name: "id"}}},
+		{Name: "uk_name", TableName: "item", IsUnique: true, Type: "BTREE", Columns: []IndexColumn{{Name: "name", SubPart: 10}}},
+		{Name: "fk_item_team", TableName: "item", Type: "BTREE", Columns: []IndexColumn{{Name: "team_id"}}},
+		{Name: "idx_team", TableName: "item", Type: "BTREE", Columns: []IndexColumn{{Name: "team_id"}}},
+	}
+	if !reflect.DeepEqual(table.Indexes, wantIndexes) {
+		t.Errorf("索引:\n got %+v\nwant %+v", table.Indexes, wantIndexes)
+	}
+	wantForeignKeys := []ForeignKey{{
+		Name: "fk_item_team", TableName: "item", Columns: []string{"team_id"},
+		RefTableName: "team", RefColumns: []string{"id"}, OnDelete: "CASCADE",
+	}}
+	if !reflect.DeepEqual(table.ForeignKeys, wantForeignKeys) {
+		t.Errorf("外键:\n got %+v\nwant %+v", table.ForeignKeys, wantForeignKeys)
+	}
+}
+
+func TestParseDDLError(t *testing.T) {
+	for _, src := range []string{
+		"CREATE TABLE t (id int",
+		"CREATE TABLE t (id int, 'x' int)",
+		"CREATE TABLE t (id varchar(10);",
+	} {
+		if _, err := ParseDDL(src); err == nil {
+			t.Errorf("%q: 期望返回错误", src)
+		}
+	}
+}
+
+//roundTripDDL DDL转换为struct再转换回DDL时应保持不变的部分
+const roundTripDDL = `CREATE TABLE users (
+  id bigint unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+  name varchar(50) NOT NULL COMMENT '名称',
+  email varchar(255) DEFAULT NULL,
+  age tinyint NOT NULL,
+  flag bit(1) NOT NULL,
+  score decimal(10,2) NOT NULL,
+  bio text,
+  created_at datetime NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY uk_email (email),
+  KEY idx_name_age (name, age)
+) COMMENT='用户';
+CREATE TABLE post (
+  id bigint unsigned NOT NULL AUTO_INCREMENT,
+  user_id bigint unsigned NOT NULL,
+  title varchar(100) NOT NULL,
+  PRIMARY KEY (id),
+  KEY idx_user (user_id),
+  CONSTRAINT fk_post_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);`
+
+//TestDDLRoundTrip DDL生成带gorm tag的struct,再由struct2table生成DDL,字段、索引和外键应与原来一致
+func TestDDLRoundTrip(t *testing.T) {
+	want, err := ParseDDL(roundTripDDL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := DefaultOptions()
+	opts.Provider = want
+	opts.TagGORM = true
+	opts.Indexes = true
+	opts.Relations = true
+	files, err := Generate(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("", "roundtrip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for _, file := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, file.Name), file.Content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, warnings, err := ParseStructFiles([]string{dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) > 0 {
+		t.Errorf("警告: %v", warnings)
+	}
+	if len(got.Tables) != len(want.Tables) {
+		t.Fatalf("期望%d张表,实际为%d", len(want.Tables), len(got.Tables))
+	}
+	for i := range want.Tables {
+		wantSQL := roundTripSQL(want.Tables[i])
+		gotSQL := roundTripSQL(got.Tables[i])
+		if gotSQL != wantSQL {
+			t.Errorf("表%s:\n got %s\nwant %s", want.Tables[i].Schema.TableName, gotSQL, wantSQL)
+		}
+		//再解析一次生成的DDL应得到相同的结果
+		reparsed, err := ParseDDL(CreateTableSQL(got.Tables[i]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sql := roundTripSQL(reparsed.Tables[0]); sql != gotSQL {
+			t.Errorf("表%s重新解析后:\n got %s\nwant %s", want.Tables[i].Schema.TableName, sql, gotSQL)
+		}
+	}
+}
+
+//roundTripSQL 按名称排列索引,外键只比较字段和动作,struct2table会重新为外键命名
+func roundTripSQL(table DDLTable) string {
+	table.Indexes = append([]Index(nil), table.Indexes...)
+	sort.Slice(table.Indexes, func(i, j int) bool { return table.Indexes[i].Name < table.Indexes[j].Name })
+	table.ForeignKeys = append([]ForeignKey(nil), table.ForeignKeys...)
+	for i := range table.ForeignKeys {
+		table.ForeignKeys[i].Name = ""
+	}
+	return CreateTableSQL(table)
+}
@@ -0,0 +1,173 @@
+package generator
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+//CreateTableSQL 生成MySQL的CREATE TABLE语句
+func CreateTableSQL(table DDLTable) string {
+	lines := make([]string, 0, len(table.Columns)+len(table.Indexes)+len(table.ForeignKeys))
+	for _, col := range table.Columns {
+		lines = append(lines, columnDefinition(col))
+	}
+	for _, index := range table.Indexes {
+		lines = append(lines, indexDefinition(index))
+	}
+	for _, fk := range table.ForeignKeys {
+		lines = append(lines, foreignKeyDefinition(fk))
+	}
+	var buf strings.Builder
+	buf.WriteString("CREATE TABLE " + quoteMySQL(table.Schema.TableName) + " (\n  ")
+	buf.WriteString(strings.Join(lines, ",\n  "))
+	buf.WriteString("\n)")
+	buf.WriteString(tableOptionsDefinition(table.Schema))
+	buf.WriteString(";\n")
+	return buf.String()
+}
+
+//tableOptionsDefinition 生成表选项,如 ENGINE=InnoDB COMMENT='用户表'
+func tableOptionsDefinition(schema TableSchema) string {
+	var options []string
+	if schema.Engine != "" {
+		options = append(options, "ENGINE="+schema.Engine)
+	}
+	if schema.TableCollation.String != "" {
+		options = append(options, "COLLATE="+schema.TableCollation.String)
+	}
+	if schema.TableComment.String != "" {
+		options = append(options, "COMMENT="+quoteMySQLString(schema.TableComment.String))
+	}
+	if len(options) == 0 {
+		return ""
+	}
+	return " " + strings.Join(options, " ")
+}
+
+//columnDefinition 生成字段定义,如`id` int(8) NOT NULL AUTO_INCREMENT
+func columnDefinition(col ColumnSchema) string {
+	parts := []string{quoteMySQL(col.ColumnName), col.ColumnType}
+	if col.CharacterSetName.String != "" {
+		parts = append(parts, "CHARACTER SET "+col.CharacterSetName.String)
+	}
+	if col.CollationName.String != "" {
+		parts = append(parts, "COLLATE "+col.CollationName.String)
+	}
+	if col.GenerationExpression != "" {
+		kind := "VIRTUAL"
+		if strings.Contains(strings.ToUpper(col.Extra.String), "STORED") {
+			kind = "STORED"
+		}
+		parts = append(parts, "GENERATED ALWAYS AS ("+col.GenerationExpression+") "+kind)
+	}
+	if col.IsNullAble == "NO" {
+		parts = append(parts, "NOT NULL")
+	}
+	if col.ColumnDefault.Valid {
+		parts = append(parts, "DEFAULT "+defaultDefinition(col))
+	} else if col.IsNullAble != "NO" && col.GenerationExpression == "" && !isBlobType(col.DataType) {
+		parts = append(parts, "DEFAULT NULL")
+	}
+	extra := strings.ToLower(col.Extra.String)
+	if strings.Contains(extra, "auto_increment") {
+		parts = append(parts, "AUTO_INCREMENT")
+	}
+	if i := strings.Index(extra, "on update "); i >= 0 {
+		parts = append(parts, "ON UPDATE "+strings.ToUpper(strings.Fields(extra[i+len("on update "):])[0]))
+	}
+	if col.ColumnComment.String != "" {
+		parts = append(parts, "COMMENT "+quoteMySQLString(col.ColumnComment.String))
+	}
+	return strings.Join(parts, " ")
+}
+
+//defaultExpressionRegexp 不需要加引号的默认值,如CURRENT_TIMESTAMP(3)、b'0'
+var defaultExpressionRegexp = regexp.MustCompile(`(?i)^(current_timestamp(\(\d*\))?|now\(\d*\)|localtime(stamp)?(\(\d*\))?|null|true|false|b'[01]*'|x'[0-9a-f]*')$`)
+
+//defaultDefinition 生成默认值,字符串加上引号
+func defaultDefinition(col ColumnSchema) string {
+	value := col.ColumnDefault.String
+	if defaultExpressionRegexp.MatchString(value) {
+		return value
+	}
+	if isDDLNumber(value) && !isStringType(col.DataType) {
+		return value
+	}
+	//MySQL 8.0的表达式默认值
+	if strings.Contains(strings.ToUpper(col.Extra.String), "DEFAULT_GENERATED") {
+		return "(" + value + ")"
+	}
+	return quoteMySQLString(value)
+}
+
+//isStringType 是否为字符串类型
+func isStringType(dataType string) bool {
+	switch strings.ToLower(dataType) {
+	case "char", "varchar", "tinytext", "text", "mediumtext", "longtext", "enum", "set", "json", "binary", "varbinary":
+		return true
+	}
+	return false
+}
+
+//isBlobType 是否为不能设置默认值的类型,与mysqldump一致不输出DEFAULT NULL
+func isBlobType(dataType string) bool {
+	switch strings.ToLower(dataType) {
+	case "tinytext", "text", "mediumtext", "longtext", "tinyblob", "blob", "mediumblob", "longblob", "json",
+		"geometry", "point", "linestring", "polygon", "multipoint", "multilinestring", "multipolygon", "geometrycollection", "geomcollection":
+		return true
+	}
+	return false
+}
+
+//indexDefinition 生成索引定义,如UNIQUE KEY `uk_name` (`name`)
+func indexDefinition(index Index) string {
+	columns := make([]string, 0, len(index.Columns))
+	for _, c := range index.Columns {
+		column := quoteMySQL(c.Name)
+		if c.SubPart > 0 {
+			column += "(" + strconv.Itoa(c.SubPart) + ")"
+		}
+		columns = append(columns, column)
+	}
+	columnList := "(" + strings.Join(columns, ",") + ")"
+	switch {
+	case index.IsPrimary:
+		return "PRIMARY KEY " + columnList
+	case index.IsUnique:
+		return "UNIQUE KEY " + quoteMySQL(index.Name) + " " + columnList
+	case strings.EqualFold(index.Type, "FULLTEXT"), strings.EqualFold(index.Type, "SPATIAL"):
+		return strings.ToUpper(index.Type) + " KEY " + quoteMySQL(index.Name) + " " + columnList
+	}
+	return "KEY " + quoteMySQL(index.Name) + " " + columnList
+}
+
+//foreignKeyDefinition 生成外键定义
+func foreignKeyDefinition(fk ForeignKey) string {
+	definition := "CONSTRAINT " + quoteMySQL(fk.Name) + " FOREIGN KEY (" + quoteMySQLList(fk.Columns) + ") REFERENCES " +
+		quoteMySQL(fk.RefTableName) + " (" + quoteMySQLList(fk.RefColumns) + ")"
+	if fk.OnDelete != "" && fk.OnDelete != "NO ACTION" && fk.OnDelete != "RESTRICT" {
+		definition += " ON DELETE " + fk.OnDelete
+	}
+	if fk.OnUpdate != "" && fk.OnUpdate != "NO ACTION" && fk.OnUpdate != "RESTRICT" {
+		definition += " ON UPDATE " + fk.OnUpdate
+	}
+	return definition
+}
+
+func quoteMySQL(name string) string {
+	return "`" + strings.Replace(name, "`", "``", -1) + "`"
+}
+
+func quoteMySQLList(names []string) string {
+	quoted := make([]string, 0, len(names))
+	for _, name := range names {
+		quoted = append(quoted, quoteMySQL(name))
+	}
+	return strings.Join(quoted, ",")
+}
+
+func quoteMySQLString(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
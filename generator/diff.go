@@ -0,0 +1,237 @@
+package generator
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+//LoadSchema 读取需要生成的表的完整结构,包括字段、索引和外键
+func (g *Generator) LoadSchema(ctx context.Context) ([]DDLTable, error) {
+	provider, err := g.Provider()
+	if err != nil {
+		return nil, err
+	}
+	tableSchemas, err := g.GetTables(ctx)
+	if err != nil {
+		return nil, err
+	}
+	foreignKeys, err := g.loadForeignKeys(ctx, provider)
+	if err != nil {
+		return nil, fmt.Errorf("读取外键失败:%v", err)
+	}
+	tables := make([]DDLTable, 0, len(tableSchemas))
+	for _, tableSchema := range tableSchemas {
+		table := DDLTable{Schema: tableSchema}
+		table.Columns, err = provider.GetColumns(ctx, tableSchema)
+		if err != nil {
+			return nil, fmt.Errorf("读取表%v失败:%v", tableSchema.TableName, err)
+		}
+		table.Indexes, err = loadIndexes(ctx, provider, tableSchema)
+		if err != nil {
+			return nil, fmt.Errorf("读取表%v的索引失败:%v", tableSchema.TableName, err)
+		}
+		for _, fk := range foreignKeys {
+			if fk.TableName == tableSchema.TableName {
+				table.ForeignKeys = append(table.ForeignKeys, fk)
+			}
+		}
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
+
+//DiffSchema 比较两组表结构,生成将from修改为to的MySQL语句。
+//新增的表生成CREATE TABLE,删除的表生成DROP TABLE,其余的差异生成ALTER TABLE,不识别表和字段的重命名
+func DiffSchema(from, to []DDLTable) []string {
+	var creates, alters, addForeignKeys, drops []string
+	for _, toTable := range to {
+		fromTable := findDDLTable(from, toTable.Schema.TableName)
+		if fromTable == nil {
+			creates = append(creates, strings.TrimSuffix(CreateTableSQL(toTable), "\n"))
+			continue
+		}
+		dropFKs, changes, addFKs := diffTable(*fromTable, toTable)
+		tableName := quoteMySQL(toTable.Schema.TableName)
+		//外键依赖索引,需要先删除外键,修改完索引后再添加
+		if len(dropFKs) > 0 {
+			alters = append(alters, alterTableSQL(tableName, dropFKs))
+		}
+		if len(changes) > 0 {
+			alters = append(alters, alterTableSQL(tableName, changes))
+		}
+		if len(addFKs) > 0 {
+			addForeignKeys = append(addForeignKeys, alterTableSQL(tableName, addFKs))
+		}
+	}
+	for _, fromTable := range from {
+		if findDDLTable(to, fromTable.Schema.TableName) == nil {
+			drops = append(drops, "DROP TABLE "+quoteMySQL(fromTable.Schema.TableName)+";")
+		}
+	}
+	statements := append(creates, alters...)
+	statements = append(statements, addForeignKeys...)
+	return append(statements, drops...)
+}
+
+func alterTableSQL(tableName string, clauses []string) string {
+	return "ALTER TABLE " + tableName + "\n  " + strings.Join(clauses, ",\n  ") + ";"
+}
+
+func findDDLTable(tables []DDLTable, name string) *DDLTable {
+	for i := range tables {
+		if strings.EqualFold(tables[i].Schema.TableName, name) {
+			return &tables[i]
+		}
+	}
+	return nil
+}
+
+//diffTable 比较同一张表的两个版本,返回需要删除的外键、其他修改和需要添加的外键
+func diffTable(from, to DDLTable) (dropFKs, changes, addFKs []string) {
+	for _, fk := range from.ForeignKeys {
+		if toFK := findForeignKey(to.ForeignKeys, fk.Name); toFK == nil || !sameForeignKey(fk, *toFK) {
+			dropFKs = append(dropFKs, "DROP FOREIGN KEY "+quoteMySQL(fk.Name))
+		}
+	}
+	for _, index := range from.Indexes {
+		if toIndex := findIndex(to.Indexes, index); toIndex == nil || !sameIndex(index, *toIndex) {
+			if index.IsPrimary {
+				changes = append(changes, "DROP PRIMARY KEY")
+			} else {
+				changes = append(changes, "DROP INDEX "+quoteMySQL(index.Name))
+			}
+		}
+	}
+	for _, col := range from.Columns {
+		if findColumn(to.Columns, col.ColumnName) == nil {
+			changes = append(changes, "DROP COLUMN "+quoteMySQL(col.ColumnName))
+		}
+	}
+	for i, col := range to.Columns {
+		fromCol := findColumn(from.Columns, col.ColumnName)
+		if fromCol == nil {
+			position := " FIRST"
+			if i > 0 {
+				position = " AFTER " + quoteMySQL(to.Columns[i-1].ColumnName)
+			}
+			changes = append(changes, "ADD COLUMN "+columnDefinition(col)+position)
+			continue
+		}
+		if !sameColumn(*fromCol, col) {
+			changes = append(changes, "MODIFY COLUMN "+columnDefinition(col))
+		}
+	}
+	for _, index := range to.Indexes {
+		if fromIndex := findIndex(from.Indexes, index); fromIndex == nil || !sameIndex(*fromIndex, index) {
+			changes = append(changes, "ADD "+indexDefinition(index))
+		}
+	}
+	if from.Schema.TableComment.String != to.Schema.TableComment.String {
+		changes = append(changes, "COMMENT="+quoteMySQLString(to.Schema.TableComment.String))
+	}
+	if from.Schema.Engine != "" && to.Schema.Engine != "" && !strings.EqualFold(from.Schema.Engine, to.Schema.Engine) {
+		changes = append(changes, "ENGINE="+to.Schema.Engine)
+	}
+	for _, fk := range to.ForeignKeys {
+		if fromFK := findForeignKey(from.ForeignKeys, fk.Name); fromFK == nil || !sameForeignKey(*fromFK, fk) {
+			addFKs = append(addFKs, "ADD "+foreignKeyDefinition(fk))
+		}
+	}
+	return dropFKs, changes, addFKs
+}
+
+func findColumn(columns []ColumnSchema, name string) *ColumnSchema {
+	for i := range columns {
+		if strings.EqualFold(columns[i].ColumnName, name) {
+			return &columns[i]
+		}
+	}
+	return nil
+}
+
+//sameColumn 比较字段定义,只有一边有字符集和排序规则时忽略(information_schema中总是有值)
+func sameColumn(a, b ColumnSchema) bool {
+	if a.CharacterSetName.String == "" || b.CharacterSetName.String == "" {
+		a.CharacterSetName, b.CharacterSetName = sql.NullString{}, sql.NullString{}
+	}
+	if a.CollationName.String == "" || b.CollationName.String == "" {
+		a.CollationName, b.CollationName = sql.NullString{}, sql.NullString{}
+	}
+	return columnDefinition(normalizeColumn(a)) == columnDefinition(normalizeColumn(b))
+}
+
+//normalizeColumn 统一大小写和MySQL 8.0中多出的Extra
+func normalizeColumn(col ColumnSchema) ColumnSchema {
+	col.ColumnType = strings.ToLower(col.ColumnType)
+	col.DataType = strings.ToLower(col.DataType)
+	extra := strings.Replace(strings.ToLower(col.Extra.String), "default_generated", "", -1)
+	col.Extra.String = strings.Join(strings.Fields(extra), " ")
+	col.CharacterSetName.String = strings.ToLower(col.CharacterSetName.String)
+	col.CollationName.String = strings.ToLower(col.CollationName.String)
+	return col
+}
+
+//findIndex 查找同名索引,主键按是否为主键查找
+func findIndex(indexes []Index, index Index) *Index {
+	for i := range indexes {
+		if index.IsPrimary && indexes[i].IsPrimary {
+			return &indexes[i]
+		}
+		if !index.IsPrimary && !indexes[i].IsPrimary && strings.EqualFold(indexes[i].Name, index.Name) {
+			return &indexes[i]
+		}
+	}
+	return nil
+}
+
+func sameIndex(a, b Index) bool {
+	if a.IsPrimary != b.IsPrimary || a.IsUnique != b.IsUnique || len(a.Columns) != len(b.Columns) {
+		return false
+	}
+	if !strings.EqualFold(indexType(a), indexType(b)) {
+		return false
+	}
+	for i := range a.Columns {
+		if !strings.EqualFold(a.Columns[i].Name, b.Columns[i].Name) || a.Columns[i].SubPart != b.Columns[i].SubPart {
+			return false
+		}
+	}
+	return true
+}
+
+//indexType 索引类型,BTREE和HASH都视为普通索引
+func indexType(index Index) string {
+	switch strings.ToUpper(index.Type) {
+	case "FULLTEXT", "SPATIAL":
+		return strings.ToUpper(index.Type)
+	}
+	return ""
+}
+
+func findForeignKey(foreignKeys []ForeignKey, name string) *ForeignKey {
+	for i := range foreignKeys {
+		if strings.EqualFold(foreignKeys[i].Name, name) {
+			return &foreignKeys[i]
+		}
+	}
+	return nil
+}
+
+func sameForeignKey(a, b ForeignKey) bool {
+	return strings.EqualFold(strings.Join(a.Columns, ","), strings.Join(b.Columns, ",")) &&
+		strings.EqualFold(a.RefTableName, b.RefTableName) &&
+		strings.EqualFold(strings.Join(a.RefColumns, ","), strings.Join(b.RefColumns, ",")) &&
+		foreignKeyAction(a.OnUpdate) == foreignKeyAction(b.OnUpdate) &&
+		foreignKeyAction(a.OnDelete) == foreignKeyAction(b.OnDelete)
+}
+
+//foreignKeyAction MySQL中NO ACTION与RESTRICT相同,也是未指定时的默认值
+func foreignKeyAction(action string) string {
+	action = strings.ToUpper(action)
+	if action == "" || action == "NO ACTION" {
+		return "RESTRICT"
+	}
+	return action
+}
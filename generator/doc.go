@@ -0,0 +1,271 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+//DefaultDocMarkdownTemplate 默认的Markdown数据字典模板
+const DefaultDocMarkdownTemplate = `# {{.Title}} 数据字典
+
+| 表名 | 注释 |
+| --- | --- |
+{{- range .Tables}}
+| [{{cell .Name}}](#{{.Anchor}}) | {{cell .Comment}} |
+{{- end}}
+{{range .Tables}}
+<a id="{{.Anchor}}"></a>
+
+## {{.Name}}
+{{if .Comment}}
+{{.Comment}}
+{{end}}
+- struct: ` + "`{{.GoName}}`" + `
+{{- if .Engine}}
+- 引擎: {{.Engine}}
+{{- end}}
+{{- if .Collation}}
+- 排序规则: {{.Collation}}
+{{- end}}
+{{- if .Rows}}
+- 行数(估计): {{.Rows}}
+{{- end}}
+
+| 字段 | 类型 | 允许为空 | 默认值 | 键 | 额外 | 注释 | Go字段 | Go类型 |
+| --- | --- | --- | --- | --- | --- | --- | --- | --- |
+{{- range .Columns}}
+| {{cell .Name}} | {{cell .Type}} | {{.Null}} | {{cell .Default}} | {{.Key}} | {{cell .Extra}} | {{cell .Comment}} | {{code .GoName}} | {{code .GoType}} |
+{{- end}}
+{{end}}`
+
+//DefaultDocHTMLTemplate 默认的HTML数据字典模板
+const DefaultDocHTMLTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}} 数据字典</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", "Microsoft YaHei", sans-serif; margin: 2em; color: #333; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { border: 1px solid #ddd; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+.comment { white-space: pre-line; }
+code { font-family: Consolas, Menlo, monospace; }
+</style>
+</head>
+<body>
+<h1>{{.Title}} 数据字典</h1>
+<table>
+<tr><th>表名</th><th>注释</th></tr>
+{{- range .Tables}}
+<tr><td><a href="#{{.Anchor}}">{{.Name}}</a></td><td class="comment">{{.Comment}}</td></tr>
+{{- end}}
+</table>
+{{- range .Tables}}
+<h2 id="{{.Anchor}}">{{.Name}}</h2>
+{{- if .Comment}}
+<p class="comment">{{.Comment}}</p>
+{{- end}}
+<ul>
+<li>struct: <code>{{.GoName}}</code></li>
+{{- if .Engine}}
+<li>引擎: {{.Engine}}</li>
+{{- end}}
+{{- if .Collation}}
+<li>排序规则: {{.Collation}}</li>
+{{- end}}
+{{- if .Rows}}
+<li>行数(估计): {{.Rows}}</li>
+{{- end}}
+</ul>
+<table>
+<tr><th>字段</th><th>类型</th><th>允许为空</th><th>默认值</th><th>键</th><th>额外</th><th>注释</th><th>Go字段</th><th>Go类型</th></tr>
+{{- range .Columns}}
+<tr><td>{{.Name}}</td><td>{{.Type}}</td><td>{{.Null}}</td><td>{{.Default}}</td><td>{{.Key}}</td><td>{{.Extra}}</td><td class="comment">{{.Comment}}</td><td><code>{{.GoName}}</code></td><td><code>{{.GoType}}</code></td></tr>
+{{- end}}
+</table>
+{{- end}}
+</body>
+</html>
+`
+
+//DocFile 渲染数据字典模板所需的数据
+type DocFile struct {
+	//Title 标题,为数据库名,未指定数据库名时为包名
+	Title string
+	//Tables 所有表
+	Tables []DocTable
+}
+
+//DocTable 数据字典中的表
+type DocTable struct {
+	Name string
+	//Anchor 表所在位置的锚点
+	Anchor  string
+	Comment string
+	//GoName 对应的struct名称
+	GoName    string
+	Engine    string
+	Collation string
+	//Rows 估计的行数,数据库没有提供时为空
+	Rows    string
+	Columns []DocColumn
+}
+
+//DocColumn 数据字典中的字段
+type DocColumn struct {
+	Name string
+	//Type 完整的字段类型,如varchar(50)
+	Type string
+	//Null 是否允许为空,为YES或NO
+	Null string
+	//Default 默认值,允许为空且没有默认值时为NULL
+	Default string
+	//Key PRI、UNI或MUL
+	Key   string
+	Extra string
+	//Comment 注释
+	Comment string
+	//GoName golang字段名
+	GoName string
+	//GoType golang类型
+	GoType string
+}
+
+//docFormats 支持的数据字典格式及文件扩展名
+var docFormats = map[string]string{
+	"markdown": ".md",
+	"html":     ".html",
+}
+
+//DocFile 生成包含所有表的数据字典数据
+func (g *Generator) DocFile(tables []Table) DocFile {
+	file := DocFile{Title: g.opts.DBName}
+	if file.Title == "" {
+		file.Title = g.opts.PackageName
+	}
+	for _, table := range tables {
+		data := g.StructData(table)
+		docTable := DocTable{
+			Name:      table.OriginName,
+			Anchor:    docAnchor(table.OriginName),
+			Comment:   table.Comment,
+			GoName:    data.GoName,
+			Engine:    table.Schema.Engine,
+			Collation: table.Schema.TableCollation.String,
+		}
+		if table.Schema.TableRows.Valid {
+			docTable.Rows = strconv.FormatInt(table.Schema.TableRows.Int64, 10)
+		}
+		for i, field := range data.Fields {
+			column := DocColumn{
+				Name:    field.Name,
+				Type:    field.OriginType,
+				Null:    "NO",
+				Default: field.Default,
+				Comment: field.Comment,
+				GoName:  field.GoName,
+				GoType:  field.Type,
+			}
+			if field.EnableNull {
+				column.Null = "YES"
+				if !field.HasDefault {
+					column.Default = "NULL"
+				}
+			}
+			//Columns为空时只能使用Field中的信息
+			if i < len(table.Columns) {
+				col := table.Columns[i]
+				column.Name = col.ColumnName
+				column.Type = col.ColumnType
+				column.Default = col.ColumnDefault.String
+				if !col.ColumnDefault.Valid && field.EnableNull {
+					column.Default = "NULL"
+				}
+				column.Key = col.ColumnKey.String
+				column.Extra = col.Extra.String
+			}
+			if column.Key == "" && field.IsPrimaryKey {
+				column.Key = "PRI"
+			}
+			if column.Extra == "" && field.IsAutoIncrement {
+				column.Extra = "auto_increment"
+			}
+			docTable.Columns = append(docTable.Columns, column)
+		}
+		file.Tables = append(file.Tables, docTable)
+	}
+	return file
+}
+
+//docAnchor 表的锚点,只保留字母、数字、下划线和连字符
+func docAnchor(name string) string {
+	return "table-" + strings.Map(func(r rune) rune {
+		if r == '_' || r == '-' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '-'
+	}, name)
+}
+
+//markdownCell 转义Markdown表格单元格中的竖线,换行转换为<br>
+func markdownCell(s string) string {
+	s = strings.Replace(s, "\r\n", "\n", -1)
+	s = strings.Replace(s, "|", "\\|", -1)
+	return strings.Replace(strings.TrimSpace(s), "\n", "<br>", -1)
+}
+
+//markdownCode 用反引号包围,为空时不包围
+func markdownCode(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "`" + strings.Replace(s, "|", "\\|", -1) + "`"
+}
+
+//docFileName 数据字典的文件名,如models.md
+func (g *Generator) docFileName(format string) string {
+	return g.opts.PackageName + docFormats[format]
+}
+
+//RenderDoc 生成包含所有表的数据字典,format为markdown或html
+func (g *Generator) RenderDoc(tables []Table, format string) ([]byte, error) {
+	buf := bytes.NewBufferString("")
+	data := g.DocFile(tables)
+	if format == "html" {
+		tpl, err := htmltemplate.New("doc").Parse(DefaultDocHTMLTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("解析模板失败:%v", err)
+		}
+		if err := tpl.Execute(buf, data); err != nil {
+			return nil, fmt.Errorf("渲染数据字典失败:%v", err)
+		}
+		return buf.Bytes(), nil
+	}
+	tpl, err := template.New("doc").Funcs(template.FuncMap{
+		"cell": markdownCell,
+		"code": markdownCode,
+	}).Parse(DefaultDocMarkdownTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("解析模板失败:%v", err)
+	}
+	if err := tpl.Execute(buf, data); err != nil {
+		return nil, fmt.Errorf("渲染数据字典失败:%v", err)
+	}
+	return append(bytes.TrimRight(buf.Bytes(), "\n"), '\n'), nil
+}
+
+//checkDoc 检查Doc选项
+func (opts *Options) checkDoc() error {
+	if opts.Doc == "" {
+		return nil
+	}
+	if _, ok := docFormats[strings.ToLower(opts.Doc)]; ok {
+		return nil
+	}
+	return fmt.Errorf("不支持的数据字典格式:%s,可选值为markdown、html", opts.Doc)
+}
@@ -0,0 +1,122 @@
+package generator
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+//EnumType 根据enum或set字段生成的类型
+type EnumType struct {
+	//GoName 类型名称,由struct名称和字段名组成,如UserStatus
+	GoName string
+	//Comment 注释
+	Comment string
+	//IsSet 是否为set字段,set字段生成位掩码类型
+	IsSet bool
+	//Values 可选值
+	Values []EnumValue
+	//ValuesVar set类型中保存所有可选值的变量名,如userTagsValues
+	ValuesVar string
+}
+
+//EnumValue enum或set的一个可选值
+type EnumValue struct {
+	//GoName 常量名称,如UserStatusActive
+	GoName string
+	//Value 数据库中的值
+	Value string
+}
+
+//parseEnumValues 从enum('a','b')或set('a','b')中解析出可选值
+func parseEnumValues(columnType string) []string {
+	start := strings.Index(columnType, "(")
+	end := strings.LastIndex(columnType, ")")
+	if start < 0 || end <= start {
+		return nil
+	}
+	var values []string
+	var buf strings.Builder
+	inString := false
+	s := columnType[start+1 : end]
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !inString {
+			if c == '\'' {
+				inString = true
+				buf.Reset()
+			}
+			continue
+		}
+		switch {
+		case c == '\\' && i+1 < len(s):
+			i++
+			buf.WriteByte(s[i])
+		case c == '\'' && i+1 < len(s) && s[i+1] == '\'':
+			i++
+			buf.WriteByte('\'')
+		case c == '\'':
+			inString = false
+			values = append(values, buf.String())
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	return values
+}
+
+//enumTypeName 获取enum或set字段生成的类型名称
+func (g *Generator) enumTypeName(structName string, field Field, tableName string) string {
+	return structName + g.GoName(field.Name, tableName)
+}
+
+//enumType 根据字段生成enum或set类型
+func (g *Generator) enumType(typeName string, field Field) EnumType {
+	enumType := EnumType{
+		GoName:  typeName,
+		Comment: field.Comment,
+		IsSet:   field.IsSet,
+		Values:  make([]EnumValue, 0, len(field.EnumValues)),
+	}
+	if enumType.Comment == "" {
+		enumType.Comment = field.Name
+	}
+	if enumType.IsSet {
+		enumType.ValuesVar = string(unicode.ToLower(rune(typeName[0]))) + typeName[1:] + "Values"
+	}
+	used := make(map[string]bool, len(field.EnumValues))
+	for _, value := range field.EnumValues {
+		name := typeName + enumValueGoName(value)
+		if used[name] {
+			base := name
+			for i := 2; used[name]; i++ {
+				name = base + strconv.Itoa(i)
+			}
+		}
+		used[name] = true
+		enumType.Values = append(enumType.Values, EnumValue{GoName: name, Value: value})
+	}
+	return enumType
+}
+
+//enumValueGoName 将enum的值转换为常量名称的后缀,如in_stock转换为InStock,空字符串转换为Empty
+func enumValueGoName(value string) string {
+	parts := strings.FieldsFunc(value, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var buf strings.Builder
+	for _, part := range parts {
+		upper := strings.ToUpper(part)
+		if inStrings(upper, commonInitialisms) {
+			buf.WriteString(upper)
+			continue
+		}
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		buf.WriteString(string(runes))
+	}
+	if buf.Len() == 0 {
+		return "Empty"
+	}
+	return buf.String()
+}
@@ -0,0 +1,408 @@
+package generator
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+)
+
+//erFormats 支持的ER图格式及文件扩展名
+var erFormats = map[string]string{
+	"mermaid":  ".mmd",
+	"plantuml": ".puml",
+	"dot":      ".dot",
+}
+
+//ERDiagram ER图
+type ERDiagram struct {
+	//Name 名称,与包名相同
+	Name string
+	//Entities 每张表对应一个实体
+	Entities []EREntity
+	//Relations 实体之间的关系
+	Relations []ERRelation
+}
+
+//EREntity ER图中的实体
+type EREntity struct {
+	//Name 表名
+	Name    string
+	Comment string
+	//Attributes 字段
+	Attributes []ERAttribute
+}
+
+//ERAttribute 实体的属性
+type ERAttribute struct {
+	Name string
+	//Type 数据库中的完整类型,如varchar(50)
+	Type string
+	//DataType 不包含长度等信息的类型,如varchar
+	DataType     string
+	Comment      string
+	EnableNull   bool
+	IsPrimaryKey bool
+	IsForeignKey bool
+}
+
+//ERRelation 实体之间的关系,由Table指向RefTable
+type ERRelation struct {
+	//Name 外键名称,推断的关系为字段名
+	Name       string
+	Table      string
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+	//EnableNull 外键字段是否允许为空,允许为空时为0或1个,否则为1个
+	EnableNull bool
+	//IsUnique 外键字段是否唯一,唯一时为一对一关系,否则为一对多关系
+	IsUnique bool
+	//IsInferred 是否根据字段名推断,而不是来自外键
+	IsInferred bool
+}
+
+//ERDiagram 根据表和外键生成ER图,只保留两端都在tables中的外键。
+//开启ERInfer时没有外键的<表名>_id字段也会被视为引用对应表的主键
+func (g *Generator) ERDiagram(tables []Table, foreignKeys []ForeignKey) ERDiagram {
+	diagram := ERDiagram{Name: g.opts.PackageName}
+	tableMap := make(map[string]Table, len(tables))
+	for _, table := range tables {
+		tableMap[table.OriginName] = table
+	}
+	fkColumns := make(map[string]map[string]bool, len(tables))
+	for _, fk := range foreignKeys {
+		table, ok := tableMap[fk.TableName]
+		if _, refOK := tableMap[fk.RefTableName]; !ok || !refOK {
+			continue
+		}
+		diagram.Relations = append(diagram.Relations, erRelation(fk.Name, table, fk.Columns, fk.RefTableName, fk.RefColumns, false))
+		if fkColumns[fk.TableName] == nil {
+			fkColumns[fk.TableName] = make(map[string]bool)
+		}
+		for _, column := range fk.Columns {
+			fkColumns[fk.TableName][column] = true
+		}
+	}
+	if g.opts.ERInfer {
+		for _, table := range tables {
+			for _, field := range table.Fields {
+				if fkColumns[table.OriginName][field.Name] {
+					continue
+				}
+				refTable, refColumn, ok := g.inferReference(table, field, tables)
+				if !ok {
+					continue
+				}
+				diagram.Relations = append(diagram.Relations, erRelation(field.Name, table, []string{field.Name}, refTable, []string{refColumn}, true))
+				if fkColumns[table.OriginName] == nil {
+					fkColumns[table.OriginName] = make(map[string]bool)
+				}
+				fkColumns[table.OriginName][field.Name] = true
+			}
+		}
+	}
+	for _, table := range tables {
+		entity := EREntity{Name: table.OriginName, Comment: table.Comment}
+		for i, field := range table.Fields {
+			attribute := ERAttribute{
+				Name:         field.Name,
+				Type:         field.OriginType,
+				DataType:     field.DataType,
+				Comment:      field.Comment,
+				EnableNull:   field.EnableNull,
+				IsPrimaryKey: field.IsPrimaryKey,
+				IsForeignKey: fkColumns[table.OriginName][field.Name],
+			}
+			if i < len(table.Columns) {
+				attribute.Type = table.Columns[i].ColumnType
+			}
+			//SQLite中没有声明类型的字段按BLOB亲和性显示
+			if attribute.Type == "" && attribute.DataType == "" {
+				attribute.Type = "blob"
+			}
+			if attribute.DataType == "" {
+				if parts := strings.Fields(attribute.Type); len(parts) > 0 {
+					attribute.DataType = strings.ToLower(parts[0])
+				}
+			}
+			entity.Attributes = append(entity.Attributes, attribute)
+		}
+		diagram.Entities = append(diagram.Entities, entity)
+	}
+	return diagram
+}
+
+//erRelation 根据外键字段是否允许为空、是否唯一确定关系
+func erRelation(name string, table Table, columns []string, refTable string, refColumns []string, inferred bool) ERRelation {
+	relation := ERRelation{
+		Name:       name,
+		Table:      table.OriginName,
+		Columns:    columns,
+		RefTable:   refTable,
+		RefColumns: refColumns,
+		IsInferred: inferred,
+	}
+	primaryKeys := primaryKeyFields(table)
+	for i, field := range table.Fields {
+		if !inStrings(field.Name, columns) {
+			continue
+		}
+		relation.EnableNull = relation.EnableNull || field.EnableNull
+		if len(columns) != 1 {
+			continue
+		}
+		if len(primaryKeys) == 1 && field.IsPrimaryKey {
+			relation.IsUnique = true
+		}
+		if i < len(table.Columns) && table.Columns[i].ColumnKey.String == "UNI" {
+			relation.IsUnique = true
+		}
+	}
+	return relation
+}
+
+//primaryKeyFields 表的主键字段
+func primaryKeyFields(table Table) []Field {
+	var fields []Field
+	for _, field := range table.Fields {
+		if field.IsPrimaryKey {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+//inferReference 根据字段名推断引用的表,如user_id引用user或users表的主键,被引用的表只能有一个主键字段
+func (g *Generator) inferReference(table Table, field Field, tables []Table) (refTable, refColumn string, ok bool) {
+	name := strings.ToLower(field.Name)
+	if !strings.HasSuffix(name, "_id") || len(name) <= 3 {
+		return "", "", false
+	}
+	base := name[:len(name)-3]
+	for _, ref := range tables {
+		refName := strings.ToLower(g.trimTablePrefix(ref.OriginName))
+		if refName != base && refName != pluralize(base) {
+			continue
+		}
+		primaryKeys := primaryKeyFields(ref)
+		if len(primaryKeys) != 1 {
+			continue
+		}
+		//主键自身不视为引用
+		if ref.OriginName == table.OriginName && primaryKeys[0].Name == field.Name {
+			continue
+		}
+		return ref.OriginName, primaryKeys[0].Name, true
+	}
+	return "", "", false
+}
+
+//erFileName ER图的文件名,如models.mmd
+func (g *Generator) erFileName(format string) string {
+	return g.opts.PackageName + erFormats[format]
+}
+
+//RenderER 生成包含所有表的ER图,format为mermaid、plantuml或dot
+func (g *Generator) RenderER(tables []Table, foreignKeys []ForeignKey, format string) ([]byte, error) {
+	diagram := g.ERDiagram(tables, foreignKeys)
+	switch format {
+	case "mermaid":
+		return []byte(diagram.Mermaid()), nil
+	case "plantuml":
+		return []byte(diagram.PlantUML()), nil
+	case "dot":
+		return []byte(diagram.DOT()), nil
+	}
+	return nil, fmt.Errorf("不支持的ER图格式:%s,可选值为mermaid、plantuml、dot", format)
+}
+
+//Mermaid 生成Mermaid的erDiagram
+func (d ERDiagram) Mermaid() string {
+	var buf strings.Builder
+	buf.WriteString("erDiagram\n")
+	for _, entity := range d.Entities {
+		buf.WriteString("    " + diagramID(entity.Name) + " {\n")
+		for _, attribute := range entity.Attributes {
+			line := "        " + diagramID(attribute.DataType) + " " + diagramID(attribute.Name)
+			if keys := attribute.keys(); len(keys) > 0 {
+				line += " " + strings.Join(keys, ", ")
+			}
+			if comment := diagramComment(attribute.Comment); comment != "" {
+				line += ` "` + comment + `"`
+			}
+			buf.WriteString(line + "\n")
+		}
+		buf.WriteString("    }\n")
+	}
+	for _, relation := range d.Relations {
+		line := "--"
+		if relation.IsInferred {
+			line = ".."
+		}
+		buf.WriteString("    " + diagramID(relation.RefTable) + " " + relation.refCardinality() + line + relation.cardinality() + " " + diagramID(relation.Table) +
+			` : "` + strings.Join(relation.Columns, ",") + "\"\n")
+	}
+	return buf.String()
+}
+
+//PlantUML 生成PlantUML的实体关系图,不允许为空的字段以*开头,主键在分隔线之上
+func (d ERDiagram) PlantUML() string {
+	var buf strings.Builder
+	buf.WriteString("@startuml\n")
+	buf.WriteString("hide circle\n")
+	buf.WriteString("skinparam linetype ortho\n")
+	for _, entity := range d.Entities {
+		title := entity.Name
+		if comment := diagramComment(entity.Comment); comment != "" {
+			title += `\n` + comment
+		}
+		buf.WriteString("\nentity \"" + title + "\" as " + diagramID(entity.Name) + " {\n")
+		hasPrimaryKey := false
+		for _, attribute := range entity.Attributes {
+			if attribute.IsPrimaryKey {
+				buf.WriteString(attribute.plantUML())
+				hasPrimaryKey = true
+			}
+		}
+		if hasPrimaryKey {
+			buf.WriteString("  --\n")
+		}
+		for _, attribute := range entity.Attributes {
+			if !attribute.IsPrimaryKey {
+				buf.WriteString(attribute.plantUML())
+			}
+		}
+		buf.WriteString("}\n")
+	}
+	if len(d.Relations) > 0 {
+		buf.WriteString("\n")
+	}
+	for _, relation := range d.Relations {
+		line := "--"
+		if relation.IsInferred {
+			line = ".."
+		}
+		buf.WriteString(diagramID(relation.RefTable) + " " + relation.refCardinality() + line + relation.cardinality() + " " + diagramID(relation.Table) +
+			" : " + strings.Join(relation.Columns, ",") + "\n")
+	}
+	buf.WriteString("@enduml\n")
+	return buf.String()
+}
+
+//plantUML PlantUML中的属性,如* id : bigint <<PK>>
+func (a ERAttribute) plantUML() string {
+	line := "  "
+	if !a.EnableNull {
+		line += "* "
+	}
+	line += a.Name + " : " + a.Type
+	for _, key := range a.keys() {
+		line += " <<" + key + ">>"
+	}
+	return line + "\n"
+}
+
+//DOT 生成Graphviz的dot文件,每张表为一个HTML表格形式的节点,关系由外键字段指向被引用的字段
+func (d ERDiagram) DOT() string {
+	var buf strings.Builder
+	buf.WriteString("digraph " + strconv.Quote(d.Name) + " {\n")
+	buf.WriteString("    graph [rankdir=LR, fontname=\"Helvetica\"];\n")
+	buf.WriteString("    node [shape=plaintext, fontname=\"Helvetica\"];\n")
+	buf.WriteString("    edge [dir=both, fontname=\"Helvetica\", fontsize=10];\n")
+	for _, entity := range d.Entities {
+		title := "<b>" + html.EscapeString(entity.Name) + "</b>"
+		if comment := diagramComment(entity.Comment); comment != "" {
+			title += "<br/>" + html.EscapeString(comment)
+		}
+		buf.WriteString("\n    " + strconv.Quote(entity.Name) + " [label=<\n")
+		buf.WriteString(`<table border="0" cellborder="1" cellspacing="0" cellpadding="4">` + "\n")
+		buf.WriteString(`<tr><td colspan="3" bgcolor="#dddddd">` + title + "</td></tr>\n")
+		for _, attribute := range entity.Attributes {
+			buf.WriteString(`<tr><td port="` + html.EscapeString(attribute.Name) + `" align="left">` + html.EscapeString(attribute.Name) + "</td>" +
+				`<td align="left">` + html.EscapeString(attribute.Type) + "</td>" +
+				"<td>" + strings.Join(attribute.keys(), ",") + "</td></tr>\n")
+		}
+		buf.WriteString("</table>>];\n")
+	}
+	if len(d.Relations) > 0 {
+		buf.WriteString("\n")
+	}
+	for _, relation := range d.Relations {
+		//尾部为外键所在的表,多的一端为crow;头部为被引用的表,允许为空时为0或1个
+		attributes := []string{"arrowtail=crow", "arrowhead=tee"}
+		if relation.IsUnique {
+			attributes[0] = "arrowtail=tee"
+		}
+		if relation.EnableNull {
+			attributes[1] = "arrowhead=teeodot"
+		}
+		if relation.IsInferred {
+			attributes = append(attributes, "style=dashed")
+		}
+		attributes = append(attributes, "label="+strconv.Quote(strings.Join(relation.Columns, ",")))
+		buf.WriteString("    " + strconv.Quote(relation.Table) + ":" + strconv.Quote(relation.Columns[0]) + " -> " +
+			strconv.Quote(relation.RefTable) + ":" + strconv.Quote(relation.RefColumns[0]) + " [" + strings.Join(attributes, ", ") + "];\n")
+	}
+	buf.WriteString("}\n")
+	return buf.String()
+}
+
+//keys 属性的PK、FK标记
+func (a ERAttribute) keys() []string {
+	var keys []string
+	if a.IsPrimaryKey {
+		keys = append(keys, "PK")
+	}
+	if a.IsForeignKey {
+		keys = append(keys, "FK")
+	}
+	return keys
+}
+
+//refCardinality 被引用的一端的基数,Mermaid和PlantUML中的||或|o
+func (r ERRelation) refCardinality() string {
+	if r.EnableNull {
+		return "|o"
+	}
+	return "||"
+}
+
+//cardinality 外键所在的一端的基数,Mermaid和PlantUML中的o{或o|
+func (r ERRelation) cardinality() string {
+	if r.IsUnique {
+		return "o|"
+	}
+	return "o{"
+}
+
+//diagramID 将名称转换为只包含字母、数字和下划线的标识符
+func diagramID(name string) string {
+	id := strings.Map(func(r rune) rune {
+		if r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, name)
+	if id == "" || id[0] >= '0' && id[0] <= '9' {
+		id = "_" + id
+	}
+	return id
+}
+
+//diagramComment 注释中的换行转换为空格,去掉Mermaid和PlantUML中无法转义的双引号
+func diagramComment(comment string) string {
+	comment = strings.Replace(comment, `"`, "", -1)
+	return strings.Join(strings.Fields(comment), " ")
+}
+
+//checkER 检查ER选项
+func (opts *Options) checkER() error {
+	if opts.ER == "" {
+		return nil
+	}
+	if _, ok := erFormats[strings.ToLower(opts.ER)]; ok {
+		return nil
+	}
+	return fmt.Errorf("不支持的ER图格式:%s,可选值为mermaid、plantuml、dot", opts.ER)
+}
@@ -0,0 +1,764 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"text/template"
+	"unicode"
+
+	//数据库驱动
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+var (
+	commonInitialisms         = []string{"API", "ASCII", "CPU", "CSS", "DNS", "EOF", "GUID", "HTML", "HTTP", "HTTPS", "ID", "IP", "JSON", "LHS", "QPS", "RAM", "RHS", "RPC", "SLA", "SMTP", "SSH", "TLS", "TTL", "UI", "UID", "UUID", "URI", "URL", "UTF8", "VM", "XML", "XSRF", "XSS"}
+	commonInitialismsReplacer *strings.Replacer
+)
+
+func init() {
+	var commonInitialismsForReplacer []string
+	for _, initialism := range commonInitialisms {
+		commonInitialismsForReplacer = append(commonInitialismsForReplacer, strings.ToLower(initialism), initialism)
+	}
+	commonInitialismsReplacer = strings.NewReplacer(commonInitialismsForReplacer...)
+}
+
+//Options 生成选项
+type Options struct {
+	//DBType 数据库类型,支持mysql、postgres
+	DBType string
+	//DBHost 数据库ip地址
+	DBHost string
+	//DBPort 数据库端口,为0时使用对应数据库的默认端口
+	DBPort int
+	//DBUser 数据库用户名
+	DBUser string
+	//DBPwd 数据库密码
+	DBPwd string
+	//DBName 数据库名
+	DBName string
+	//DBSchema PostgreSQL的schema
+	DBSchema string
+	//DBSSLMode PostgreSQL的sslmode
+	DBSSLMode string
+	//DDLFile 包含CREATE TABLE语句的sql文件,不为空时不连接数据库
+	DDLFile string
+	//SQLiteFile SQLite数据库文件
+	SQLiteFile string
+	//SchemaFile json格式的表结构快照,不为空时不连接数据库
+	SchemaFile string
+	//Provider 自定义表结构来源,不为空时忽略上面的数据库设置
+	Provider Provider
+
+	//Tables 只生成指定的表,为空时生成所有表
+	Tables []string
+	//PackageName 包名
+	PackageName string
+	//TablePrefix 表名前缀
+	TablePrefix string
+	//SkipIfNoPrefix 当表名不包含指定前缀时跳过不处理
+	SkipIfNoPrefix bool
+
+	//TagJSON 是否生成json的tag
+	TagJSON bool
+	//TagSQLX 是否生成sqlx的tag
+	TagSQLX bool
+	//TagGORM 是否生成gorm的tag
+	TagGORM bool
+	//TagGORMType 是否将type包含进gorm的tag
+	TagGORMType bool
+	//TagXORM 是否生成xorm的tag
+	TagXORM bool
+	//TagXORMType 是否将type包含进xorm的tag
+	TagXORMType bool
+	//TagValidate 是否根据字段的长度、是否为空等生成go-playground/validator的validate tag
+	TagValidate bool
+	//ValidateRequired 是否为不允许为空且没有默认值的字段生成required规则,自增字段和bool字段除外
+	ValidateRequired bool
+
+	//UseInt64 是否将tinyint、smallint等类型也转换int64
+	UseInt64 bool
+	//UseUnsigned 当表中字段为无符号整型时是否在go中也转换为uint的形式
+	UseUnsigned bool
+	//NullType 当字段允许为空时是否用复合类型(如sql.NullInt64)代替
+	NullType bool
+	//ExtNullType 用go-nulltype取代database/sql
+	ExtNullType bool
+	//NullStrategy 允许为空的字段的处理方式,可选值为sql、nulltype、pointer、generic、guregu,不为空时忽略NullType和ExtNullType
+	NullStrategy string
+
+	//Mapping 字段映射规则,如foo:Bar、table1.foo:Bar,type:int64
+	Mapping []string
+	//MappingFile 字段映射文件,每行一条映射规则
+	MappingFile string
+	//Mappings 结构化的字段映射,键为表名(global表示所有表)和字段名,优先级低于Mapping和MappingFile
+	Mappings map[string]map[string]Mapping
+	//TableOptions 针对单个表的设置,键为数据库中的表名
+	TableOptions map[string]TableOptions
+	//FallbackType 无法识别的数据库类型转换后的类型
+	FallbackType string
+	//Imports 包名(别名)对应的导入路径,用于映射后的类型,如decimal:github.com/shopspring/decimal
+	Imports map[string]string
+	//TypeRules 自定义的类型映射规则,按顺序匹配,优先级高于内置的规则
+	TypeRules []TypeRule
+	//Relations 是否根据外键生成关联字段
+	Relations bool
+	//Enums 是否为enum和set字段生成单独的类型
+	Enums bool
+	//Indexes 是否读取索引,生成gorm、xorm的索引tag和Indexes方法
+	Indexes bool
+	//Columns 是否生成保存表名和字段名的<struct名>Columns变量、Columns和FieldByColumn方法
+	Columns bool
+	//TemplateFile 自定义的struct模板文件(text/template),为空时使用DefaultTemplate
+	TemplateFile string
+	//Repo 是否生成基于sqlx的增删改查函数(<表名>_repo.go),开启后会同时生成sqlx的tag
+	Repo bool
+	//Proto 是否生成包含所有表的proto文件(<ProtoPackage>.proto),每张表对应一个message
+	Proto bool
+	//ProtoPackage proto文件的package,为空时与PackageName相同
+	ProtoPackage string
+	//ProtoGoPackage proto文件的go_package,也是ToProto和FromProto中导入的protoc生成的包
+	ProtoGoPackage string
+	//ProtoConvert 是否生成struct与message互相转换的ToProto和FromProto方法(<表名>_proto.go),开启后会同时生成proto文件
+	ProtoConvert bool
+	//TypeScript 是否生成包含所有表的TypeScript interface(<PackageName>.ts)
+	TypeScript bool
+	//OpenAPI 生成包含所有表的OpenAPI文档(openapi.yaml或openapi.json)的格式,可选值为yaml、json,为空时不生成
+	OpenAPI string
+	//JSONSchema 是否为每张表生成JSON Schema(<表名>.schema.json)
+	JSONSchema bool
+	//Doc 生成包含所有表的数据字典的格式,可选值为markdown、html,为空时不生成
+	Doc string
+	//ER 生成包含所有表的ER图的格式,可选值为mermaid、plantuml、dot,为空时不生成
+	ER string
+	//ERInfer 是否根据<表名>_id形式的字段名推断没有外键的关系
+	ERInfer bool
+}
+
+//DefaultOptions 默认选项,与命令行参数的默认值一致
+func DefaultOptions() Options {
+	return Options{
+		DBType:           "mysql",
+		DBHost:           "127.0.0.1",
+		DBUser:           "root",
+		DBPwd:            "root",
+		DBSchema:         "public",
+		DBSSLMode:        "disable",
+		PackageName:      "models",
+		FallbackType:     "string",
+		TagJSON:          true,
+		TagGORMType:      true,
+		TagXORMType:      true,
+		ValidateRequired: true,
+	}
+}
+
+//TableOptions 针对单个表的设置
+type TableOptions struct {
+	//Skip 是否跳过该表
+	Skip bool
+	//StructName struct名称,为空时根据表名转换
+	StructName string
+	//Comment 表注释,为空时使用数据库中的注释
+	Comment string
+	//Mappings 该表的字段映射,键为字段名
+	Mappings map[string]Mapping
+}
+
+//GeneratedFile 生成的文件
+type GeneratedFile struct {
+	//Name 文件名,相对于输出目录
+	Name string
+	//Table 对应的表
+	Table Table
+	//Content 文件内容
+	Content []byte
+}
+
+//Generator 代码生成器
+type Generator struct {
+	opts     Options
+	mapping  map[string]map[string]Mapping
+	provider Provider
+	db       *sqlx.DB
+	tpl      *template.Template
+	repoTpl  *template.Template
+
+	typeRules []TypeRule
+	warnings  []string
+
+	foreignKeys       []ForeignKey
+	foreignKeysLoaded bool
+
+	//tables 本次生成的表,直接调用RenderTable时为空
+	tables map[string]bool
+}
+
+//New 创建Generator,会解析映射规则,但直到需要读取表结构时才会连接数据库
+func New(opts Options) (*Generator, error) {
+	if err := opts.checkNullStrategy(); err != nil {
+		return nil, err
+	}
+	if err := opts.checkOpenAPI(); err != nil {
+		return nil, err
+	}
+	if err := opts.checkDoc(); err != nil {
+		return nil, err
+	}
+	if err := opts.checkER(); err != nil {
+		return nil, err
+	}
+	//增删改查函数通过db tag映射字段
+	if opts.Repo {
+		opts.TagSQLX = true
+	}
+	if opts.ProtoConvert {
+		if opts.ProtoGoPackage == "" {
+			return nil, fmt.Errorf("生成ToProto和FromProto时需要指定proto的go_package")
+		}
+		opts.Proto = true
+	}
+	g := &Generator{
+		opts: opts,
+		mapping: map[string]map[string]Mapping{
+			"global": make(map[string]Mapping),
+		},
+		provider: opts.Provider,
+	}
+	for tableName, mappings := range opts.Mappings {
+		for columnName, mapping := range mappings {
+			g.setMapping(tableName, columnName, mapping)
+		}
+	}
+	for tableName, tableOptions := range opts.TableOptions {
+		for columnName, mapping := range tableOptions.Mappings {
+			g.setMapping(tableName, columnName, mapping)
+			//生成代码时使用的是去掉前缀的表名
+			if opts.TablePrefix != "" && strings.HasPrefix(tableName, opts.TablePrefix) {
+				g.setMapping(tableName[len(opts.TablePrefix):], columnName, mapping)
+			}
+		}
+	}
+	//从文件中解析映射规则
+	if opts.MappingFile != "" {
+		mappingFileContent, err := ioutil.ReadFile(opts.MappingFile)
+		if err != nil {
+			return nil, fmt.Errorf("读取映射文件失败:%v", err)
+		}
+		for _, mappingStr := range strings.Split(string(mappingFileContent), "\n") {
+			mappingStr = strings.TrimSpace(mappingStr)
+			if mappingStr == "" {
+				continue
+			}
+			if err := g.addMapping(mappingStr); err != nil {
+				return nil, fmt.Errorf("映射文件格式错误: %v", err)
+			}
+		}
+	}
+	//从参数中解析映射规则
+	for _, mappingStr := range opts.Mapping {
+		if err := g.addMapping(mappingStr); err != nil {
+			return nil, err
+		}
+	}
+	for i, rule := range opts.TypeRules {
+		if err := rule.compile(); err != nil {
+			return nil, fmt.Errorf("第%d条类型映射规则错误:%v", i+1, err)
+		}
+		g.typeRules = append(g.typeRules, rule)
+	}
+	tpl, err := g.loadTemplate()
+	if err != nil {
+		return nil, err
+	}
+	g.tpl = tpl
+	return g, nil
+}
+
+//Generate 根据选项读取表结构并生成代码
+func Generate(ctx context.Context, opts Options) ([]GeneratedFile, error) {
+	g, err := New(opts)
+	if err != nil {
+		return nil, err
+	}
+	defer g.Close()
+	return g.Generate(ctx)
+}
+
+//RenderTable 将表转换为格式化后的golang代码
+func RenderTable(table Table, opts Options) ([]byte, error) {
+	g, err := New(opts)
+	if err != nil {
+		return nil, err
+	}
+	return g.RenderTable(table)
+}
+
+//Close 关闭由Generator打开的数据库连接
+func (g *Generator) Close() error {
+	if g.db != nil {
+		return g.db.Close()
+	}
+	return nil
+}
+
+//Warnings 返回生成过程中的警告,如无法识别的类型
+func (g *Generator) Warnings() []string {
+	return g.warnings
+}
+
+//warnf 记录一条警告,相同的警告只记录一次
+func (g *Generator) warnf(format string, args ...interface{}) {
+	warning := fmt.Sprintf(format, args...)
+	if !inStrings(warning, g.warnings) {
+		g.warnings = append(g.warnings, warning)
+	}
+}
+
+//Options 返回生成选项
+func (g *Generator) Options() Options {
+	return g.opts
+}
+
+//Provider 返回表结构来源,首次调用时根据选项打开数据库或读取DDL文件、表结构快照
+func (g *Generator) Provider() (Provider, error) {
+	if g.provider != nil {
+		return g.provider, nil
+	}
+	opts := g.opts
+	if opts.DDLFile != "" {
+		ddlContent, err := ioutil.ReadFile(opts.DDLFile)
+		if err != nil {
+			return nil, fmt.Errorf("读取DDL文件失败:%v", err)
+		}
+		ddlSchema, err := ParseDDL(string(ddlContent))
+		if err != nil {
+			return nil, fmt.Errorf("解析DDL文件失败:%v", err)
+		}
+		g.provider = ddlSchema
+		return g.provider, nil
+	}
+	if opts.SchemaFile != "" {
+		snapshot, err := ReadSchemaSnapshot(opts.SchemaFile)
+		if err != nil {
+			return nil, err
+		}
+		g.provider, err = snapshot.Provider()
+		if err != nil {
+			return nil, err
+		}
+		return g.provider, nil
+	}
+	if opts.SQLiteFile != "" {
+		if _, err := os.Stat(opts.SQLiteFile); err != nil {
+			return nil, fmt.Errorf("读取SQLite数据库文件失败:%v", err)
+		}
+		db, err := sqlx.Open("sqlite3", opts.SQLiteFile)
+		if err != nil {
+			return nil, fmt.Errorf("打开SQLite数据库文件失败:%v", err)
+		}
+		g.db = db
+		g.provider = NewSQLiteProvider(db)
+		return g.provider, nil
+	}
+	if opts.DBName == "" {
+		return nil, fmt.Errorf("请输入数据库名称")
+	}
+	var err error
+	switch opts.DBType {
+	case "", "mysql":
+		if opts.DBPort == 0 {
+			opts.DBPort = 3306
+		}
+		g.db, err = sqlx.Open("mysql", fmt.Sprintf("%s:%s@tcp(%s:%d)/information_schema?parseTime=true", opts.DBUser, opts.DBPwd, opts.DBHost, opts.DBPort))
+		g.provider = NewMySQLProvider(g.db, opts.DBName)
+	case "postgres":
+		if opts.DBPort == 0 {
+			opts.DBPort = 5432
+		}
+		g.db, err = sqlx.Open("postgres", fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s", opts.DBHost, opts.DBPort, opts.DBUser, opts.DBPwd, opts.DBName, opts.DBSSLMode))
+		g.provider = NewPostgresProvider(g.db, opts.DBSchema)
+	default:
+		return nil, fmt.Errorf("不支持的数据库类型:%v", opts.DBType)
+	}
+	if err != nil {
+		g.provider = nil
+		return nil, fmt.Errorf("连接数据库失败:%v", err)
+	}
+	return g.provider, nil
+}
+
+//GetTables 获取需要生成的表
+func (g *Generator) GetTables(ctx context.Context) ([]TableSchema, error) {
+	provider, err := g.Provider()
+	if err != nil {
+		return nil, err
+	}
+	tableSchemas, err := provider.GetTables(ctx, append([]string(nil), g.opts.Tables...))
+	if err != nil {
+		return nil, fmt.Errorf("读取数据库表失败:%v", err)
+	}
+	filtered := tableSchemas[:0]
+	for _, tableSchema := range tableSchemas {
+		//当表名不包含指定前缀时跳过
+		if g.opts.TablePrefix != "" && g.opts.SkipIfNoPrefix && !strings.Contains(tableSchema.TableName, g.opts.TablePrefix) {
+			continue
+		}
+		if g.opts.TableOptions[tableSchema.TableName].Skip {
+			continue
+		}
+		filtered = append(filtered, tableSchema)
+	}
+	return filtered, nil
+}
+
+//GetTable 获取表
+func (g *Generator) GetTable(ctx context.Context, tableSchema TableSchema) (Table, error) {
+	provider, err := g.Provider()
+	if err != nil {
+		return Table{}, err
+	}
+	cols, err := provider.GetColumns(ctx, tableSchema)
+	if err != nil {
+		return Table{}, fmt.Errorf("读取表%v失败:%v", tableSchema.TableName, err)
+	}
+	table := g.buildTable(provider, tableSchema, cols)
+	if g.opts.Indexes {
+		table.Indexes, err = loadIndexes(ctx, provider, tableSchema)
+		if err != nil {
+			return Table{}, fmt.Errorf("读取表%v的索引失败:%v", tableSchema.TableName, err)
+		}
+	}
+	if g.opts.Relations {
+		foreignKeys, err := g.loadForeignKeys(ctx, provider)
+		if err != nil {
+			return Table{}, fmt.Errorf("读取外键失败:%v", err)
+		}
+		for _, fk := range foreignKeys {
+			if fk.TableName == table.OriginName {
+				table.ForeignKeys = append(table.ForeignKeys, fk)
+			}
+			if fk.RefTableName == table.OriginName {
+				table.ReferencedBy = append(table.ReferencedBy, fk)
+			}
+		}
+	}
+	return table, nil
+}
+
+//Generate 读取表结构并生成代码
+func (g *Generator) Generate(ctx context.Context) ([]GeneratedFile, error) {
+	tableSchemas, err := g.GetTables(ctx)
+	if err != nil {
+		return nil, err
+	}
+	g.tables = make(map[string]bool, len(tableSchemas))
+	for _, tableSchema := range tableSchemas {
+		g.tables[tableSchema.TableName] = true
+	}
+	files := make([]GeneratedFile, 0, len(tableSchemas))
+	tables := make([]Table, 0, len(tableSchemas))
+	for _, tableSchema := range tableSchemas {
+		table, err := g.GetTable(ctx, tableSchema)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+		content, err := g.RenderTable(table)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, GeneratedFile{
+			Name:    table.Name + ".go",
+			Table:   table,
+			Content: content,
+		})
+		if g.opts.Repo {
+			content, err := g.RenderRepo(table)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, GeneratedFile{
+				Name:    table.Name + "_repo.go",
+				Table:   table,
+				Content: content,
+			})
+		}
+		if g.opts.JSONSchema {
+			content, err := g.RenderJSONSchema(table)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, GeneratedFile{
+				Name:    table.Name + ".schema.json",
+				Table:   table,
+				Content: content,
+			})
+		}
+		if g.opts.ProtoConvert {
+			content, err := g.RenderProtoConvert(table)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, GeneratedFile{
+				Name:    table.Name + "_proto.go",
+				Table:   table,
+				Content: content,
+			})
+		}
+	}
+	if g.opts.Proto {
+		content, err := g.RenderProto(tables)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, GeneratedFile{
+			Name:    g.protoFileName(),
+			Content: content,
+		})
+	}
+	if g.opts.OpenAPI != "" {
+		format := strings.ToLower(g.opts.OpenAPI)
+		content, err := g.RenderOpenAPI(tables, format)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, GeneratedFile{
+			Name:    "openapi." + format,
+			Content: content,
+		})
+	}
+	if g.opts.TypeScript {
+		content, err := g.RenderTypeScript(tables)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, GeneratedFile{
+			Name:    g.typeScriptFileName(),
+			Content: content,
+		})
+	}
+	if g.opts.Doc != "" {
+		format := strings.ToLower(g.opts.Doc)
+		content, err := g.RenderDoc(tables, format)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, GeneratedFile{
+			Name:    g.docFileName(format),
+			Content: content,
+		})
+	}
+	if g.opts.ER != "" {
+		provider, err := g.Provider()
+		if err != nil {
+			return nil, err
+		}
+		foreignKeys, err := g.loadForeignKeys(ctx, provider)
+		if err != nil {
+			return nil, fmt.Errorf("读取外键失败:%v", err)
+		}
+		format := strings.ToLower(g.opts.ER)
+		content, err := g.RenderER(tables, foreignKeys, format)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, GeneratedFile{
+			Name:    g.erFileName(format),
+			Content: content,
+		})
+	}
+	return files, nil
+}
+
+//buildTable 根据表和字段信息生成Table
+func (g *Generator) buildTable(p Provider, tableSchema TableSchema, cols []ColumnSchema) Table {
+	table := Table{
+		Fields:  make([]Field, 0, len(cols)),
+		Columns: cols,
+	}
+	table.Comment = tableSchema.TableComment.String
+	table.Schema = tableSchema
+	table.OriginName = tableSchema.TableName
+	table.Name = tableSchema.TableName
+	if g.opts.TablePrefix != "" {
+		if strings.HasPrefix(tableSchema.TableName, g.opts.TablePrefix) {
+			table.Name = tableSchema.TableName[len(g.opts.TablePrefix):]
+		}
+	}
+	structName := g.StructName(tableSchema.TableName)
+	for _, col := range cols {
+		field := p.ParseField(col, &g.opts)
+		field.DataType = strings.ToLower(col.DataType)
+		field.Position = int(col.OrdinalPosition.Int64)
+		field.Length, field.DecimalDigits = columnLength(col)
+		field.IsGenerated = col.GenerationExpression != ""
+		field.HasDefault = col.ColumnDefault.Valid || field.IsGenerated
+		if g.opts.Enums && len(field.EnumValues) > 0 {
+			field.Type = g.enumTypeName(structName, field, table.Name)
+			if field.EnableNull && g.opts.nullStrategy() != NullStrategyNone {
+				field.Type = "*" + field.Type
+			}
+			field.IsNullType = false
+			field.IsExtNullType = false
+		}
+		g.applyTypeRules(&field, col)
+		g.applyMapping(&field, col.TableName)
+		if field.Type == "" {
+			fallbackType := g.opts.FallbackType
+			if fallbackType == "" {
+				fallbackType = "string"
+			}
+			field.Type, field.IsNullType, field.IsExtNullType = g.opts.ruleType(TypeRule{GoType: fallbackType}, field.EnableNull)
+			g.warnf("表%s的字段%s: 未知类型%s,已转换为%s", col.TableName, col.ColumnName, col.ColumnType, field.Type)
+		}
+		if field.Type == "time.Time" {
+			table.HasTime = true
+		}
+		table.Fields = append(table.Fields, field)
+	}
+	return table
+}
+
+//GoName 将数据库字段名转换为golang字段名,参考 github.com/jinzhu/gorm 的 ToDBName
+func (g *Generator) GoName(dbName string, tableName string) string {
+	if m, ok := g.mapping[tableName]; ok {
+		if mapping, goNameOK := m[dbName]; goNameOK && mapping.FieldName != "" {
+			return mapping.FieldName
+		}
+	}
+	if m, ok := g.mapping["global"]; ok {
+		if mapping, goNameOK := m[dbName]; goNameOK && mapping.FieldName != "" {
+			return mapping.FieldName
+		}
+	}
+	if len(dbName) == 1 {
+		return strings.ToUpper(dbName)
+	}
+	var value string
+	for i, v := range dbName {
+		if (v >= 'A' && v <= 'Z') || (v >= 'a' && v <= 'z') {
+			value = dbName[i:]
+			break
+		}
+	}
+	value = commonInitialismsReplacer.Replace(value)
+	buf := bytes.NewBufferString("")
+	for i, v := range value[:len(value)-1] {
+		if i > 0 {
+			if v == '_' || v == '-' {
+				continue
+			}
+			if value[i-1] == '_' {
+				buf.WriteRune(unicode.ToUpper(v))
+			} else {
+				buf.WriteRune(v)
+			}
+		} else {
+			buf.WriteRune(unicode.ToUpper(v))
+		}
+	}
+	buf.WriteByte(value[len(value)-1])
+	return buf.String()
+}
+
+//addMapping 增加映射
+func (g *Generator) addMapping(m string) error {
+	if strings.Count(m, ":") == 0 {
+		return fmt.Errorf("映射格式错误: [%s]", m)
+	}
+	index := strings.Index(m, ":")
+	if index == 0 || index >= len(m)-2 {
+		return fmt.Errorf("映射格式错误: [%s]", m)
+	}
+	origin := m[0:index]
+	dest := m[index+1:]
+	var originName string
+	tableName := "global"
+	if strings.Contains(origin, ".") {
+		m2 := strings.Split(origin, ".")
+		if len(m2) != 2 {
+			return fmt.Errorf("映射格式错误: [%s]", m)
+		}
+		tableName, originName = m2[0], m2[1]
+	} else {
+		originName = origin
+	}
+	mapping := Mapping{}
+	if strings.Contains(dest, ",") {
+		m3 := strings.Split(dest, ",")
+		mapping.FieldName = m3[0]
+		for i := 1; i < len(m3); i++ {
+			attr := strings.Split(m3[i], ":")
+			if attr[0] == "type" {
+				mapping.FieldType = attr[1]
+			}
+		}
+	} else {
+		mapping.FieldName = dest
+	}
+
+	g.setMapping(tableName, originName, mapping)
+	return nil
+}
+
+//setMapping 设置映射,tableName为global时对所有表生效
+func (g *Generator) setMapping(tableName, columnName string, mapping Mapping) {
+	if _, ok := g.mapping[tableName]; !ok {
+		g.mapping[tableName] = make(map[string]Mapping)
+	}
+	g.mapping[tableName][columnName] = mapping
+}
+
+//applyMapping 如果映射中有设定数据类型则从映射中获取数据类型
+func (g *Generator) applyMapping(field *Field, tableName string) {
+	if m, ok := g.mapping["global"]; ok {
+		if mapping, ok := m[field.Name]; ok && mapping.FieldType != "" {
+			field.Type = mapping.FieldType
+			field.Import = ""
+		}
+	}
+	if m, ok := g.mapping[tableName]; ok {
+		if mapping, ok := m[field.Name]; ok && mapping.FieldType != "" {
+			field.Type = mapping.FieldType
+			field.Import = ""
+		}
+	}
+	//无视映射规则中的大小写
+	switch strings.ToUpper(field.Type) {
+	case "SQL.NULLINT64":
+		field.IsNullType = true
+		field.Type = "sql.NullInt64"
+	case "SQL.NULLSTRING":
+		field.IsNullType = true
+		field.Type = "sql.NullString"
+	case "SQL.NULLBOOL":
+		field.IsNullType = true
+		field.Type = "sql.NullBool"
+	case "SQL.NULLFLOAT64":
+		field.IsNullType = true
+		field.Type = "sql.NullFloat64"
+	case "NULLTYPE.NULLINT64":
+		field.IsExtNullType = true
+		field.Type = "nulltype.NullInt64"
+	case "NULLTYPE.NULLSTRING":
+		field.IsExtNullType = true
+		field.Type = "nulltype.NullString"
+	case "NULLTYPE.NULLBOOL":
+		field.IsExtNullType = true
+		field.Type = "nulltype.NullBool"
+	case "NULLTYPE.NULLFLOAT64":
+		field.IsExtNullType = true
+		field.Type = "nulltype.NullFloat64"
+	case "NULLTYPE.NULLTIME":
+		field.IsExtNullType = true
+		field.Type = "nulltype.NullTime"
+	}
+}
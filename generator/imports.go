@@ -0,0 +1,80 @@
+package generator
+
+import (
+	"path"
+	"regexp"
+	"sort"
+)
+
+//knownImports 常用包的导入路径,键为代码中使用的包名
+var knownImports = map[string]string{
+	"big":       "math/big",
+	"datatypes": "gorm.io/datatypes",
+	"decimal":   "github.com/shopspring/decimal",
+	"driver":    "database/sql/driver",
+	"json":      "encoding/json",
+	"net":       "net",
+	"null":      "gopkg.in/guregu/null.v4",
+	"nulltype":  "github.com/mattn/go-nulltype",
+	"pq":        "github.com/lib/pq",
+	"sql":       "database/sql",
+	"time":      "time",
+	"uuid":      "github.com/google/uuid",
+}
+
+//qualifierRegexp 匹配类型中的包名,如map[string]decimal.Decimal中的decimal
+var qualifierRegexp = regexp.MustCompile(`([A-Za-z_][A-Za-z0-9_]*)\.[A-Za-z_]`)
+
+//typeQualifiers 获取类型中用到的包名
+func typeQualifiers(goType string) []string {
+	var qualifiers []string
+	for _, match := range qualifierRegexp.FindAllStringSubmatch(goType, -1) {
+		qualifiers = append(qualifiers, match[1])
+	}
+	return qualifiers
+}
+
+//importPath 获取包名对应的导入路径,Imports选项优先于内置的常用包
+func (g *Generator) importPath(qualifier string) (string, bool) {
+	if importPath, ok := g.opts.Imports[qualifier]; ok {
+		return importPath, true
+	}
+	importPath, ok := knownImports[qualifier]
+	return importPath, ok
+}
+
+//importSpec 生成import语句中的一项,包名与导入路径的最后一部分不一致时加上别名
+func importSpec(qualifier, importPath string) string {
+	if qualifier != path.Base(importPath) {
+		return qualifier + ` "` + importPath + `"`
+	}
+	return `"` + importPath + `"`
+}
+
+//fieldImports 根据字段类型生成需要导入的包,类型映射规则中指定的包优先
+func (g *Generator) fieldImports(tableName string, fields []Field) []string {
+	paths := make(map[string]string)
+	for _, field := range fields {
+		for i, qualifier := range typeQualifiers(field.Type) {
+			if _, ok := paths[qualifier]; ok {
+				continue
+			}
+			if i == 0 && field.Import != "" {
+				paths[qualifier] = field.Import
+				continue
+			}
+			importPath, ok := g.importPath(qualifier)
+			if !ok {
+				g.warnf("表%s的字段%s: 类型%s中的包%s没有对应的导入路径,请在imports中设置", tableName, field.Name, field.Type, qualifier)
+				continue
+			}
+			paths[qualifier] = importPath
+		}
+	}
+	imports := make([]string, 0, len(paths))
+	for qualifier, importPath := range paths {
+		imports = append(imports, importSpec(qualifier, importPath))
+	}
+	sort.Strings(imports)
+	return imports
+}
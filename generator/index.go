@@ -0,0 +1,135 @@
+package generator
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//Index 索引
+type Index struct {
+	//Name 索引名称,MySQL中主键的名称为PRIMARY
+	Name string `json:"name"`
+	//TableName 索引所在的表
+	TableName string `json:"-"`
+	//IsPrimary 是否为主键
+	IsPrimary bool `json:"primary,omitempty"`
+	//IsUnique 是否为唯一索引
+	IsUnique bool `json:"unique,omitempty"`
+	//Type 索引类型,如BTREE、FULLTEXT、SPATIAL
+	Type string `json:"type,omitempty"`
+	//Columns 按顺序排列的索引字段
+	Columns []IndexColumn `json:"columns"`
+}
+
+//IndexColumn 索引中的一个字段
+type IndexColumn struct {
+	//Name 字段名
+	Name string `json:"name"`
+	//SubPart 前缀索引的长度,为0时表示索引整个字段
+	SubPart int `json:"sub_part,omitempty"`
+}
+
+//IndexProvider 能够读取索引的表结构来源
+type IndexProvider interface {
+	//GetIndexes 获取表的所有索引
+	GetIndexes(ctx context.Context, tableSchema TableSchema) ([]Index, error)
+}
+
+//String 返回字段在索引定义中的写法,如name(10)
+func (c IndexColumn) String() string {
+	if c.SubPart > 0 {
+		return c.Name + "(" + strconv.Itoa(c.SubPart) + ")"
+	}
+	return c.Name
+}
+
+//ColumnNames 返回索引的字段名
+func (idx Index) ColumnNames() []string {
+	names := make([]string, 0, len(idx.Columns))
+	for _, c := range idx.Columns {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+//ColumnDefs 返回索引字段在索引定义中的写法,如[]string{"name(10)", "age"}
+func (idx Index) ColumnDefs() []string {
+	defs := make([]string, 0, len(idx.Columns))
+	for _, c := range idx.Columns {
+		defs = append(defs, c.String())
+	}
+	return defs
+}
+
+//loadIndexes 读取表的索引,主键排在最前面,其余按名称排序,Provider不支持索引时返回空
+func loadIndexes(ctx context.Context, provider Provider, tableSchema TableSchema) ([]Index, error) {
+	indexProvider, ok := provider.(IndexProvider)
+	if !ok {
+		return nil, nil
+	}
+	indexes, err := indexProvider.GetIndexes(ctx, tableSchema)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		if indexes[i].IsPrimary != indexes[j].IsPrimary {
+			return indexes[i].IsPrimary
+		}
+		return indexes[i].Name < indexes[j].Name
+	})
+	return indexes, nil
+}
+
+//gormIndexTags 生成字段所在索引的gorm tag,如index:idx_name,priority:2
+func gormIndexTags(indexes []Index, columnName string) []string {
+	var tags []string
+	for _, idx := range indexes {
+		if idx.IsPrimary {
+			continue
+		}
+		for i, c := range idx.Columns {
+			if c.Name != columnName {
+				continue
+			}
+			tag := "index:" + idx.Name
+			if idx.IsUnique {
+				tag = "uniqueIndex:" + idx.Name
+			}
+			switch strings.ToUpper(idx.Type) {
+			case "FULLTEXT", "SPATIAL":
+				tag += ",class:" + strings.ToUpper(idx.Type)
+			}
+			if c.SubPart > 0 {
+				tag += ",length:" + strconv.Itoa(c.SubPart)
+			}
+			if len(idx.Columns) > 1 {
+				tag += ",priority:" + strconv.Itoa(i+1)
+			}
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+//xormIndexTags 生成字段所在索引的xorm tag,如index(idx_name)、unique(idx_name)
+func xormIndexTags(indexes []Index, columnName string) []string {
+	var tags []string
+	for _, idx := range indexes {
+		if idx.IsPrimary {
+			continue
+		}
+		for _, c := range idx.Columns {
+			if c.Name != columnName {
+				continue
+			}
+			if idx.IsUnique {
+				tags = append(tags, "unique("+idx.Name+")")
+			} else {
+				tags = append(tags, "index("+idx.Name+")")
+			}
+		}
+	}
+	return tags
+}
@@ -0,0 +1,89 @@
+package generator
+
+import "strings"
+
+//jsonType 字段的golang类型序列化为json后的类型,TypeScript和OpenAPI、JSON Schema都由它转换
+type jsonType struct {
+	//Kind 为string、int、uint、float、bool、time、bytes、decimal、json、enum、set、array、object之一,无法识别的类型为空
+	Kind string
+	//Bits int、uint和float的位数
+	Bits int
+	//GoType 去掉指针后的golang类型,enum和set为生成的类型名称或string
+	GoType string
+	//Elem array中元素的类型,或者object中保存值的字段的类型
+	Elem *jsonType
+	//Member object中保存值的字段,如sql.NullString序列化为{"String":"","Valid":false}
+	Member string
+	//Nullable 字段允许为空
+	Nullable bool
+}
+
+//jsonKinds golang类型序列化为json后的类型
+var jsonKinds = map[string]jsonType{
+	"int": {Kind: "int", Bits: 64}, "int8": {Kind: "int", Bits: 8}, "int16": {Kind: "int", Bits: 16}, "int32": {Kind: "int", Bits: 32}, "int64": {Kind: "int", Bits: 64},
+	"uint": {Kind: "uint", Bits: 64}, "uint8": {Kind: "uint", Bits: 8}, "uint16": {Kind: "uint", Bits: 16}, "uint32": {Kind: "uint", Bits: 32}, "uint64": {Kind: "uint", Bits: 64},
+	"float32": {Kind: "float", Bits: 32}, "float64": {Kind: "float", Bits: 64},
+	"string":    {Kind: "string"},
+	"bool":      {Kind: "bool"},
+	"time.Time": {Kind: "time"},
+	//[]byte序列化为base64字符串
+	"[]byte": {Kind: "bytes"},
+	//decimal.Decimal默认序列化为字符串,以免丢失精度
+	"decimal.Decimal": {Kind: "decimal"},
+	"json.RawMessage": {Kind: "json"},
+	//nulltype和guregu的类型序列化为值或null
+	"nulltype.NullString":  {Kind: "string"},
+	"nulltype.NullInt64":   {Kind: "int", Bits: 64},
+	"nulltype.NullFloat64": {Kind: "float", Bits: 64},
+	"nulltype.NullBool":    {Kind: "bool"},
+	"nulltype.NullTime":    {Kind: "time"},
+	"null.String":          {Kind: "string"},
+	"null.Int":             {Kind: "int", Bits: 64},
+	"null.Float":           {Kind: "float", Bits: 64},
+	"null.Bool":            {Kind: "bool"},
+	"null.Time":            {Kind: "time"},
+}
+
+//jsonArrayItems pq的数组类型中元素的golang类型
+var jsonArrayItems = map[string]string{
+	"pq.StringArray":  "string",
+	"pq.Int64Array":   "int64",
+	"pq.Float64Array": "float64",
+	"pq.BoolArray":    "bool",
+	"pq.ByteaArray":   "[]byte",
+}
+
+//fieldJSONType 字段序列化为json后的类型,是否为null由字段是否允许为空决定,与null_strategy无关
+func fieldJSONType(field StructField, enumTypes map[string]bool) jsonType {
+	goType := strings.TrimPrefix(field.Type, "*")
+	var t jsonType
+	if member, ok := structNullMembers[goType]; ok && strings.HasPrefix(goType, "sql.") {
+		//database/sql中的类型没有实现json.Marshaler
+		elem := goJSONType(member[1], field.Field, enumTypes)
+		t = jsonType{Kind: "object", GoType: goType, Elem: &elem, Member: member[0]}
+	} else if strings.HasPrefix(goType, "sql.Null[") && strings.HasSuffix(goType, "]") {
+		elem := goJSONType(goType[len("sql.Null["):len(goType)-1], field.Field, enumTypes)
+		t = jsonType{Kind: "object", GoType: goType, Elem: &elem, Member: "V"}
+	} else {
+		t = goJSONType(goType, field.Field, enumTypes)
+	}
+	t.Nullable = field.EnableNull || strings.HasPrefix(field.Type, "*")
+	return t
+}
+
+//goJSONType 不允许为空的golang类型序列化为json后的类型
+func goJSONType(goType string, field Field, enumTypes map[string]bool) jsonType {
+	switch {
+	case enumTypes[goType] && field.IsSet:
+		//set类型没有实现json.Marshaler,序列化为数字
+		return jsonType{Kind: "set", GoType: goType}
+	case (enumTypes[goType] || goType == "string") && len(field.EnumValues) > 0 && !field.IsSet:
+		return jsonType{Kind: "enum", GoType: goType}
+	case jsonArrayItems[goType] != "":
+		elem := goJSONType(jsonArrayItems[goType], Field{}, nil)
+		return jsonType{Kind: "array", GoType: goType, Elem: &elem}
+	}
+	t := jsonKinds[goType]
+	t.GoType = goType
+	return t
+}
@@ -0,0 +1,177 @@
+package generator
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+//MySQLProvider 从MySQL的information_schema中读取表结构
+type MySQLProvider struct {
+	db     *sqlx.DB
+	dbName string
+}
+
+//NewMySQLProvider 创建MySQLProvider
+func NewMySQLProvider(db *sqlx.DB, dbName string) *MySQLProvider {
+	return &MySQLProvider{db: db, dbName: dbName}
+}
+
+//GetTables 获取所有表
+func (p *MySQLProvider) GetTables(ctx context.Context, args []string) ([]TableSchema, error) {
+	tables := make([]TableSchema, 0, 32)
+	whereTables := ""
+	if len(args) > 0 {
+		for i := range args {
+			args[i] = "'" + args[i] + "'"
+		}
+		whereTables = " AND TABLE_NAME IN (" + strings.Join(args, ",") + ")"
+	}
+	sqlStr := fmt.Sprintf("SELECT TABLE_CATALOG,TABLE_SCHEMA,TABLE_NAME,TABLE_TYPE,ENGINE,`VERSION`,ROW_FORMAT,TABLE_ROWS,AVG_ROW_LENGTH,DATA_LENGTH,MAX_DATA_LENGTH,INDEX_LENGTH,DATA_FREE,`AUTO_INCREMENT`,CREATE_TIME,UPDATE_TIME,CHECK_TIME,TABLE_COLLATION,CHECKSUM,CREATE_OPTIONS,TABLE_COMMENT FROM information_schema.tables WHERE `TABLE_SCHEMA` = '%s'%s", p.dbName, whereTables)
+	rows, err := p.db.QueryxContext(ctx, sqlStr)
+
+	if err != nil {
+		return tables, err
+	}
+	var table TableSchema
+	for rows.Next() {
+		if err = rows.StructScan(&table); err != nil {
+			return tables, err
+		}
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
+
+//GetColumns 获取表的所有字段
+func (p *MySQLProvider) GetColumns(ctx context.Context, tableSchema TableSchema) ([]ColumnSchema, error) {
+	rows, err := p.db.QueryxContext(ctx, fmt.Sprintf("SELECT `TABLE_CATALOG`,`TABLE_SCHEMA`,`TABLE_NAME`,`COLUMN_NAME`,`ORDINAL_POSITION`,`COLUMN_DEFAULT`,`IS_NULLABLE`,`DATA_TYPE`,`CHARACTER_MAXIMUM_LENGTH`,`CHARACTER_OCTET_LENGTH`,`NUMERIC_PRECISION`,`NUMERIC_SCALE`,`DATETIME_PRECISION`,`CHARACTER_SET_NAME`,`COLLATION_NAME`,`COLUMN_TYPE`,`COLUMN_KEY`,`EXTRA`,`PRIVILEGES`,`COLUMN_COMMENT`,`GENERATION_EXPRESSION` FROM information_schema.columns WHERE `TABLE_SCHEMA` = '%s' AND `TABLE_NAME` = '%s' ORDER BY `ORDINAL_POSITION`", p.dbName, tableSchema.TableName))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	cols := make([]ColumnSchema, 0, 16)
+	var col ColumnSchema
+	for rows.Next() {
+		if err = rows.StructScan(&col); err != nil {
+			return nil, err
+		}
+		cols = append(cols, col)
+	}
+	return cols, nil
+}
+
+//ParseField 解析字段
+func (p *MySQLProvider) ParseField(col ColumnSchema, opts *Options) Field {
+	var field Field
+	if strings.Contains(col.ColumnType, "unsigned") {
+		field.IsUnsigned = true
+	}
+	if col.IsNullAble == "YES" {
+		field.EnableNull = true
+	}
+	if strings.Contains(col.ColumnKey.String, "PRI") {
+		field.IsPrimaryKey = true
+	}
+	if strings.Contains(col.Extra.String, "auto_increment") {
+		field.IsAutoIncrement = true
+	}
+	field.Name = col.ColumnName
+	field.Type, field.IsNullType, field.IsExtNullType = opts.matchTypeRules(mysqlTypeRules(opts), col, field.EnableNull)
+
+	if col.DataType == "enum" || col.DataType == "set" {
+		field.EnumValues = parseEnumValues(col.ColumnType)
+		field.IsSet = col.DataType == "set"
+	}
+
+	field.Comment = col.ColumnComment.String
+	field.Default = col.ColumnDefault.String
+	field.OriginType = col.ColumnType
+	return field
+}
+
+//GoType 将MySQL的数据类型转换为golang类型,无法识别的类型返回空字符串
+func (p *MySQLProvider) GoType(dbType string, isNullAble bool, opts *Options) (goType string, isNullType bool, IsExtNullType bool) {
+	return opts.matchTypeRules(mysqlTypeRules(opts), ColumnSchema{DataType: dbType, ColumnType: dbType}, isNullAble)
+}
+
+//mysqlForeignKeyColumn KEY_COLUMN_USAGE中外键的一个字段
+type mysqlForeignKeyColumn struct {
+	ConstraintName       string `db:"CONSTRAINT_NAME"`
+	TableName            string `db:"TABLE_NAME"`
+	ColumnName           string `db:"COLUMN_NAME"`
+	ReferencedTableName  string `db:"REFERENCED_TABLE_NAME"`
+	ReferencedColumnName string `db:"REFERENCED_COLUMN_NAME"`
+	UpdateRule           string `db:"UPDATE_RULE"`
+	DeleteRule           string `db:"DELETE_RULE"`
+}
+
+//GetForeignKeys 获取所有外键
+func (p *MySQLProvider) GetForeignKeys(ctx context.Context) ([]ForeignKey, error) {
+	var cols []mysqlForeignKeyColumn
+	err := p.db.SelectContext(ctx, &cols, "SELECT k.`CONSTRAINT_NAME`,k.`TABLE_NAME`,k.`COLUMN_NAME`,k.`REFERENCED_TABLE_NAME`,k.`REFERENCED_COLUMN_NAME`,r.`UPDATE_RULE`,r.`DELETE_RULE` FROM information_schema.KEY_COLUMN_USAGE k JOIN information_schema.REFERENTIAL_CONSTRAINTS r ON r.`CONSTRAINT_SCHEMA` = k.`CONSTRAINT_SCHEMA` AND r.`TABLE_NAME` = k.`TABLE_NAME` AND r.`CONSTRAINT_NAME` = k.`CONSTRAINT_NAME` WHERE k.`TABLE_SCHEMA` = ? AND k.`REFERENCED_TABLE_NAME` IS NOT NULL ORDER BY k.`TABLE_NAME`,k.`CONSTRAINT_NAME`,k.`ORDINAL_POSITION`", p.dbName)
+	if err != nil {
+		return nil, err
+	}
+	var foreignKeys []ForeignKey
+	for _, col := range cols {
+		n := len(foreignKeys)
+		if n == 0 || foreignKeys[n-1].TableName != col.TableName || foreignKeys[n-1].Name != col.ConstraintName {
+			foreignKeys = append(foreignKeys, ForeignKey{
+				Name:         col.ConstraintName,
+				TableName:    col.TableName,
+				RefTableName: col.ReferencedTableName,
+				OnUpdate:     col.UpdateRule,
+				OnDelete:     col.DeleteRule,
+			})
+			n++
+		}
+		foreignKeys[n-1].Columns = append(foreignKeys[n-1].Columns, col.ColumnName)
+		foreignKeys[n-1].RefColumns = append(foreignKeys[n-1].RefColumns, col.ReferencedColumnName)
+	}
+	return foreignKeys, nil
+}
+
+//mysqlIndexColumn information_schema.STATISTICS中索引的一个字段
+type mysqlIndexColumn struct {
+	IndexName  string         `db:"INDEX_NAME"`
+	NonUnique  int            `db:"NON_UNIQUE"`
+	ColumnName sql.NullString `db:"COLUMN_NAME"`
+	SubPart    sql.NullInt64  `db:"SUB_PART"`
+	IndexType  string         `db:"INDEX_TYPE"`
+}
+
+//GetIndexes 获取表的所有索引
+func (p *MySQLProvider) GetIndexes(ctx context.Context, tableSchema TableSchema) ([]Index, error) {
+	var cols []mysqlIndexColumn
+	err := p.db.SelectContext(ctx, &cols, "SELECT `INDEX_NAME`,`NON_UNIQUE`,`COLUMN_NAME`,`SUB_PART`,`INDEX_TYPE` FROM information_schema.STATISTICS WHERE `TABLE_SCHEMA` = ? AND `TABLE_NAME` = ? ORDER BY `INDEX_NAME`,`SEQ_IN_INDEX`", p.dbName, tableSchema.TableName)
+	if err != nil {
+		return nil, err
+	}
+	var indexes []Index
+	for _, col := range cols {
+		n := len(indexes)
+		if n == 0 || indexes[n-1].Name != col.IndexName {
+			indexes = append(indexes, Index{
+				Name:      col.IndexName,
+				TableName: tableSchema.TableName,
+				IsPrimary: col.IndexName == "PRIMARY",
+				IsUnique:  col.NonUnique == 0,
+				Type:      col.IndexType,
+			})
+			n++
+		}
+		//MySQL 8.0的函数索引没有字段名
+		if !col.ColumnName.Valid {
+			continue
+		}
+		indexes[n-1].Columns = append(indexes[n-1].Columns, IndexColumn{
+			Name:    col.ColumnName.String,
+			SubPart: int(col.SubPart.Int64),
+		})
+	}
+	return indexes, nil
+}
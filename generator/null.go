@@ -0,0 +1,106 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+//允许为空的字段的处理方式
+const (
+	//NullStrategyNone 不做处理,与不允许为空的字段类型相同
+	NullStrategyNone = "none"
+	//NullStrategySQL 使用database/sql中的类型,如sql.NullInt64
+	NullStrategySQL = "sql"
+	//NullStrategyNullType 使用github.com/mattn/go-nulltype中的类型,如nulltype.NullInt64
+	NullStrategyNullType = "nulltype"
+	//NullStrategyPointer 使用指针,如*int64
+	NullStrategyPointer = "pointer"
+	//NullStrategyGeneric 使用Go 1.22的泛型类型,如sql.Null[int64]
+	NullStrategyGeneric = "generic"
+	//NullStrategyGuregu 使用gopkg.in/guregu/null.v4中的类型,如null.Int
+	NullStrategyGuregu = "guregu"
+)
+
+//checkNullStrategy 检查NullStrategy选项
+func (opts *Options) checkNullStrategy() error {
+	switch opts.NullStrategy {
+	case "", NullStrategyNone, NullStrategySQL, NullStrategyNullType, NullStrategyPointer, NullStrategyGeneric, NullStrategyGuregu:
+		return nil
+	}
+	return fmt.Errorf("不支持的null_strategy:%s,可选值为sql、nulltype、pointer、generic、guregu", opts.NullStrategy)
+}
+
+//nullStrategy 返回允许为空的字段的处理方式,未指定NullStrategy时根据NullType和ExtNullType决定
+func (opts *Options) nullStrategy() string {
+	if opts.NullStrategy != "" {
+		return opts.NullStrategy
+	}
+	if !opts.NullType {
+		return NullStrategyNone
+	}
+	if opts.ExtNullType {
+		return NullStrategyNullType
+	}
+	return NullStrategySQL
+}
+
+//nullableType 根据null_strategy将允许为空的字段转换为对应的类型,
+//没有对应类型时uint64等内置类型使用指针,其他包中的类型保持不变
+func (opts *Options) nullableType(baseType string, isNullAble bool) (goType string, isNullType bool, isExtNullType bool) {
+	strategy := opts.nullStrategy()
+	if !isNullAble || strategy == NullStrategyNone {
+		return baseType, false, false
+	}
+	//切片、map、指针和pq中的数组类型本身可以表示NULL
+	if strings.HasPrefix(baseType, "[]") || strings.HasPrefix(baseType, "map[") || strings.HasPrefix(baseType, "*") ||
+		strings.HasPrefix(baseType, "pq.") && strings.HasSuffix(baseType, "Array") {
+		return baseType, false, false
+	}
+	switch strategy {
+	case NullStrategyPointer:
+		return "*" + baseType, false, false
+	case NullStrategyGeneric:
+		return "sql.Null[" + baseType + "]", true, false
+	case NullStrategyGuregu:
+		switch baseType {
+		case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32":
+			return "null.Int", false, false
+		case "float32", "float64":
+			return "null.Float", false, false
+		case "bool":
+			return "null.Bool", false, false
+		case "string":
+			return "null.String", false, false
+		case "time.Time":
+			return "null.Time", false, false
+		}
+		return nullablePointer(baseType), false, false
+	}
+	var name string
+	switch baseType {
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32":
+		name = "NullInt64"
+	case "float32", "float64":
+		name = "NullFloat64"
+	case "bool":
+		name = "NullBool"
+	case "string":
+		name = "NullString"
+	case "time.Time":
+		name = "NullTime"
+	default:
+		return nullablePointer(baseType), false, false
+	}
+	if strategy == NullStrategyNullType {
+		return "nulltype." + name, false, true
+	}
+	return "sql." + name, true, false
+}
+
+//nullablePointer 没有对应的可为空类型时,内置类型使用指针以免丢失NULL
+func nullablePointer(baseType string) string {
+	if strings.Contains(baseType, ".") {
+		return baseType
+	}
+	return "*" + baseType
+}
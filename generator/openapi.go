@@ -0,0 +1,245 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+//OpenAPIVersion 生成的OpenAPI文档的版本
+const OpenAPIVersion = "3.0.3"
+
+//JSONSchemaDialect 生成的JSON Schema使用的版本
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+//schemaObject 保持键顺序的对象,用于生成OpenAPI和JSON Schema
+type schemaObject []schemaItem
+
+type schemaItem struct {
+	Key   string
+	Value interface{}
+}
+
+//MarshalJSON 按顺序输出键
+func (o schemaObject) MarshalJSON() ([]byte, error) {
+	buf := bytes.NewBufferString("{")
+	for i, item := range o {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		key, err := json.Marshal(item.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(item.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteString(":")
+		buf.Write(value)
+	}
+	buf.WriteString("}")
+	return buf.Bytes(), nil
+}
+
+//MarshalYAML 按顺序输出键
+func (o schemaObject) MarshalYAML() (interface{}, error) {
+	slice := make(yaml.MapSlice, 0, len(o))
+	for _, item := range o {
+		slice = append(slice, yaml.MapItem{Key: item.Key, Value: item.Value})
+	}
+	return slice, nil
+}
+
+func (o schemaObject) set(key string, value interface{}) schemaObject {
+	return append(o, schemaItem{Key: key, Value: value})
+}
+
+//fieldSchema 字段的golang类型序列化为json后对应的schema,允许为空的字段可以为null,
+//jsonSchema为true时用JSON Schema的方式表示null,否则使用OpenAPI 3.0的nullable
+func fieldSchema(field StructField, enumTypes map[string]bool, jsonSchema bool) schemaObject {
+	t := fieldJSONType(field, enumTypes)
+	return jsonTypeSchema(t, field.Field, t.Nullable, jsonSchema)
+}
+
+//jsonTypeSchema 序列化后的类型对应的schema,无法识别的类型不限制type
+func jsonTypeSchema(t jsonType, field Field, nullable, jsonSchema bool) schemaObject {
+	var typ, format string
+	var items, properties schemaObject
+	var required []string
+	var enum []interface{}
+	switch t.Kind {
+	case "string", "decimal":
+		typ = "string"
+	case "time":
+		typ, format = "string", "date-time"
+	case "bytes":
+		//[]byte序列化为base64字符串
+		typ, format = "string", "byte"
+	case "bool":
+		typ = "boolean"
+	case "int":
+		typ, format = "integer", "int64"
+		if t.Bits <= 32 {
+			format = "int32"
+		}
+	case "uint":
+		//uint和uint64超出了int64的范围
+		switch {
+		case t.Bits < 32:
+			typ, format = "integer", "int32"
+		case t.Bits == 32:
+			typ, format = "integer", "int64"
+		default:
+			typ = "integer"
+		}
+	case "float":
+		typ, format = "number", "double"
+		if t.Bits == 32 {
+			format = "float"
+		}
+	case "set":
+		typ = "integer"
+	case "enum":
+		typ = "string"
+		enum = make([]interface{}, 0, len(field.EnumValues)+1)
+		for _, value := range field.EnumValues {
+			enum = append(enum, value)
+		}
+		//允许为空时null也需要在enum中
+		if nullable {
+			enum = append(enum, nil)
+		}
+	case "array":
+		typ = "array"
+		items = jsonTypeSchema(*t.Elem, Field{}, false, jsonSchema)
+	case "object":
+		typ = "object"
+		required = []string{t.Member, "Valid"}
+		properties = schemaObject{}.
+			set(t.Member, jsonTypeSchema(*t.Elem, field, false, jsonSchema)).
+			set("Valid", schemaObject{}.set("type", "boolean"))
+	}
+	schema := schemaObject{}
+	if typ == "" {
+		return schema
+	}
+	if nullable && jsonSchema {
+		schema = schema.set("type", []string{typ, "null"})
+	} else {
+		schema = schema.set("type", typ)
+	}
+	if format != "" {
+		schema = schema.set("format", format)
+	}
+	if nullable && !jsonSchema {
+		schema = schema.set("nullable", true)
+	}
+	if required != nil {
+		schema = schema.set("required", required).set("properties", properties)
+	}
+	if items != nil {
+		schema = schema.set("items", items)
+	}
+	if t.Kind == "string" && field.Length > 0 && !isDecimalType(field.OriginType) {
+		schema = schema.set("maxLength", field.Length)
+	}
+	if (typ == "integer" || typ == "number") && (field.IsUnsigned || t.Kind == "uint" || t.Kind == "set") {
+		schema = schema.set("minimum", 0)
+	}
+	if enum != nil {
+		schema = schema.set("enum", enum)
+	}
+	return schema
+}
+
+//tableSchemaObject 表对应的schema,jsonSchema为true时用JSON Schema的方式表示null,否则使用OpenAPI 3.0的nullable
+func (g *Generator) tableSchemaObject(table Table, jsonSchema bool) schemaObject {
+	data := g.StructData(table)
+	enumTypes := make(map[string]bool, len(data.Enums))
+	for _, enum := range data.Enums {
+		enumTypes[enum.GoName] = true
+	}
+	var properties schemaObject
+	var required []string
+	for _, field := range data.Fields {
+		name, omitEmpty, ok := jsonName(field.TagValue("json"), field.GoName)
+		if !ok {
+			continue
+		}
+		property := fieldSchema(field, enumTypes, jsonSchema)
+		if field.Comment != "" {
+			property = property.set("description", field.Comment)
+		}
+		if field.IsAutoIncrement || field.IsGenerated {
+			property = property.set("readOnly", true)
+		}
+		properties = properties.set(name, property)
+		//omitempty的字段为零值时不会出现在json中
+		if !field.EnableNull && !omitEmpty {
+			required = append(required, name)
+		}
+	}
+	var schema schemaObject
+	if jsonSchema {
+		schema = schema.set("$schema", JSONSchemaDialect).set("title", data.GoName)
+	}
+	schema = schema.set("type", "object")
+	if table.Comment != "" {
+		schema = schema.set("description", table.Comment)
+	}
+	if len(required) > 0 {
+		schema = schema.set("required", required)
+	}
+	if properties == nil {
+		properties = schemaObject{}
+	}
+	return schema.set("properties", properties)
+}
+
+//RenderOpenAPI 生成包含所有表的OpenAPI文档,format为yaml或json
+func (g *Generator) RenderOpenAPI(tables []Table, format string) ([]byte, error) {
+	schemas := schemaObject{}
+	for _, table := range tables {
+		schemas = schemas.set(g.StructName(table.OriginName), g.tableSchemaObject(table, false))
+	}
+	doc := schemaObject{}.
+		set("openapi", OpenAPIVersion).
+		set("info", schemaObject{}.set("title", g.opts.PackageName).set("version", "1.0.0")).
+		set("paths", schemaObject{}).
+		set("components", schemaObject{}.set("schemas", schemas))
+	if format == "json" {
+		content, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("生成OpenAPI文档失败:%v", err)
+		}
+		return append(content, '\n'), nil
+	}
+	content, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("生成OpenAPI文档失败:%v", err)
+	}
+	return content, nil
+}
+
+//RenderJSONSchema 生成表的JSON Schema
+func (g *Generator) RenderJSONSchema(table Table) ([]byte, error) {
+	content, err := json.MarshalIndent(g.tableSchemaObject(table, true), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("生成表%s的JSON Schema失败:%v", table.Name, err)
+	}
+	return append(content, '\n'), nil
+}
+
+//checkOpenAPI 检查OpenAPI选项
+func (opts *Options) checkOpenAPI() error {
+	switch strings.ToLower(opts.OpenAPI) {
+	case "", "yaml", "json":
+		return nil
+	}
+	return fmt.Errorf("不支持的openapi格式:%s,可选值为yaml、json", opts.OpenAPI)
+}
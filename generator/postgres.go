@@ -0,0 +1,236 @@
+package generator
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+//PostgresProvider 从PostgreSQL的information_schema和pg_catalog中读取表结构
+type PostgresProvider struct {
+	db     *sqlx.DB
+	schema string
+}
+
+//NewPostgresProvider 创建PostgresProvider
+func NewPostgresProvider(db *sqlx.DB, schema string) *PostgresProvider {
+	return &PostgresProvider{db: db, schema: schema}
+}
+
+//GetTables 获取所有表
+func (p *PostgresProvider) GetTables(ctx context.Context, args []string) ([]TableSchema, error) {
+	tables := make([]TableSchema, 0, 32)
+	sqlStr := `SELECT t.table_catalog AS "TABLE_CATALOG", t.table_schema AS "TABLE_SCHEMA", t.table_name AS "TABLE_NAME", t.table_type AS "TABLE_TYPE",
+	CAST(c.reltuples AS BIGINT) AS "TABLE_ROWS", obj_description(c.oid, 'pg_class') AS "TABLE_COMMENT"
+FROM information_schema.tables t
+JOIN pg_catalog.pg_namespace n ON n.nspname = t.table_schema
+JOIN pg_catalog.pg_class c ON c.relnamespace = n.oid AND c.relname = t.table_name
+WHERE t.table_schema = $1`
+	queryArgs := []interface{}{p.schema}
+	if len(args) > 0 {
+		sqlStr += ` AND t.table_name = ANY($2)`
+		queryArgs = append(queryArgs, pq.Array(args))
+	}
+	rows, err := p.db.QueryxContext(ctx, sqlStr+` ORDER BY t.table_name`, queryArgs...)
+	if err != nil {
+		return tables, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var table TableSchema
+		if err = rows.StructScan(&table); err != nil {
+			return tables, err
+		}
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
+
+//GetColumns 获取表的所有字段
+//
+//DATA_TYPE使用udt_name(如int4、timestamptz、_text),域类型会被还原为其基础类型,枚举类型的DATA_TYPE为enum
+func (p *PostgresProvider) GetColumns(ctx context.Context, tableSchema TableSchema) ([]ColumnSchema, error) {
+	sqlStr := `SELECT col.table_catalog AS "TABLE_CATALOG", col.table_schema AS "TABLE_SCHEMA", col.table_name AS "TABLE_NAME", col.column_name AS "COLUMN_NAME",
+	col.ordinal_position AS "ORDINAL_POSITION", col.column_default AS "COLUMN_DEFAULT", col.is_nullable AS "IS_NULLABLE",
+	CASE WHEN t.typtype = 'e' THEN 'enum' WHEN bt.typtype = 'e' THEN 'enum' WHEN t.typtype = 'd' THEN bt.typname ELSE col.udt_name END AS "DATA_TYPE",
+	col.character_maximum_length AS "CHARACTER_MAXIMUM_LENGTH", col.character_octet_length AS "CHARACTER_OCTET_LENGTH",
+	col.numeric_precision AS "NUMERIC_PRECISION", col.numeric_scale AS "NUMERIC_SCALE", col.datetime_precision AS "DATETIME_PRECISION",
+	col.character_set_name AS "CHARACTER_SET_NAME", col.collation_name AS "COLLATION_NAME",
+	format_type(a.atttypid, a.atttypmod) AS "COLUMN_TYPE",
+	CASE
+		WHEN EXISTS (SELECT 1 FROM pg_catalog.pg_index i WHERE i.indrelid = c.oid AND i.indisprimary AND a.attnum = ANY(i.indkey)) THEN 'PRI'
+		WHEN EXISTS (SELECT 1 FROM pg_catalog.pg_index i WHERE i.indrelid = c.oid AND i.indisunique AND i.indnatts = 1 AND a.attnum = ANY(i.indkey)) THEN 'UNI'
+		WHEN EXISTS (SELECT 1 FROM pg_catalog.pg_index i WHERE i.indrelid = c.oid AND a.attnum = i.indkey[0]) THEN 'MUL'
+		ELSE ''
+	END AS "COLUMN_KEY",
+	CASE WHEN col.is_identity = 'YES' OR col.column_default LIKE 'nextval(%' THEN 'auto_increment' ELSE '' END AS "EXTRA",
+	col_description(c.oid, a.attnum) AS "COLUMN_COMMENT",
+	(SELECT string_agg(quote_literal(e.enumlabel), ',' ORDER BY e.enumsortorder) FROM pg_catalog.pg_enum e
+		WHERE e.enumtypid = CASE WHEN t.typtype = 'd' THEN bt.oid ELSE t.oid END) AS "ENUM_VALUES",
+	COALESCE(col.generation_expression, '') AS "GENERATION_EXPRESSION"
+FROM information_schema.columns col
+JOIN pg_catalog.pg_namespace n ON n.nspname = col.table_schema
+JOIN pg_catalog.pg_class c ON c.relnamespace = n.oid AND c.relname = col.table_name
+JOIN pg_catalog.pg_attribute a ON a.attrelid = c.oid AND a.attname = col.column_name
+JOIN pg_catalog.pg_type t ON t.oid = a.atttypid
+LEFT JOIN pg_catalog.pg_type bt ON bt.oid = t.typbasetype
+WHERE col.table_schema = $1 AND col.table_name = $2
+ORDER BY col.ordinal_position`
+	rows, err := p.db.QueryxContext(ctx, sqlStr, p.schema, tableSchema.TableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	cols := make([]ColumnSchema, 0, 16)
+	for rows.Next() {
+		var col ColumnSchema
+		if err = rows.StructScan(&col); err != nil {
+			return nil, err
+		}
+		cols = append(cols, col)
+	}
+	return cols, nil
+}
+
+//ParseField 解析字段
+func (p *PostgresProvider) ParseField(col ColumnSchema, opts *Options) Field {
+	var field Field
+	if col.IsNullAble == "YES" {
+		field.EnableNull = true
+	}
+	if strings.Contains(col.ColumnKey.String, "PRI") {
+		field.IsPrimaryKey = true
+	}
+	if strings.Contains(col.Extra.String, "auto_increment") {
+		field.IsAutoIncrement = true
+	}
+	field.Name = col.ColumnName
+	field.Type, field.IsNullType, field.IsExtNullType = p.GoType(col.DataType, field.EnableNull, opts)
+	if col.DataType == "enum" {
+		field.EnumValues = parseEnumValues("enum(" + col.EnumValues.String + ")")
+	}
+	field.Comment = col.ColumnComment.String
+	field.Default = col.ColumnDefault.String
+	field.OriginType = col.ColumnType
+	return field
+}
+
+//GoType 将PostgreSQL的数据类型(udt_name)转换为golang类型,无法识别的类型返回空字符串
+func (p *PostgresProvider) GoType(dbType string, isNullAble bool, opts *Options) (goType string, isNullType bool, isExtNullType bool) {
+	return opts.matchTypeRules(postgresTypeRules(opts), ColumnSchema{DataType: dbType, ColumnType: dbType}, isNullAble)
+}
+
+//pgForeignKeyColumn pg_constraint中外键的一个字段
+type pgForeignKeyColumn struct {
+	ConstraintName       string `db:"constraint_name"`
+	TableName            string `db:"table_name"`
+	ColumnName           string `db:"column_name"`
+	ReferencedTableName  string `db:"referenced_table_name"`
+	ReferencedColumnName string `db:"referenced_column_name"`
+	UpdateRule           string `db:"update_rule"`
+	DeleteRule           string `db:"delete_rule"`
+}
+
+//GetForeignKeys 获取所有外键
+func (p *PostgresProvider) GetForeignKeys(ctx context.Context) ([]ForeignKey, error) {
+	var cols []pgForeignKeyColumn
+	err := p.db.SelectContext(ctx, &cols, `SELECT con.conname AS constraint_name, cl.relname AS table_name, a.attname AS column_name,
+	rcl.relname AS referenced_table_name, ra.attname AS referenced_column_name,
+	con.confupdtype AS update_rule, con.confdeltype AS delete_rule
+FROM pg_catalog.pg_constraint con
+JOIN pg_catalog.pg_class cl ON cl.oid = con.conrelid
+JOIN pg_catalog.pg_namespace n ON n.oid = cl.relnamespace
+JOIN pg_catalog.pg_class rcl ON rcl.oid = con.confrelid
+CROSS JOIN LATERAL unnest(con.conkey, con.confkey) WITH ORDINALITY AS k(attnum, refattnum, ord)
+JOIN pg_catalog.pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+JOIN pg_catalog.pg_attribute ra ON ra.attrelid = con.confrelid AND ra.attnum = k.refattnum
+WHERE con.contype = 'f' AND n.nspname = $1
+ORDER BY cl.relname, con.conname, k.ord`, p.schema)
+	if err != nil {
+		return nil, err
+	}
+	var foreignKeys []ForeignKey
+	for _, col := range cols {
+		n := len(foreignKeys)
+		if n == 0 || foreignKeys[n-1].TableName != col.TableName || foreignKeys[n-1].Name != col.ConstraintName {
+			foreignKeys = append(foreignKeys, ForeignKey{
+				Name:         col.ConstraintName,
+				TableName:    col.TableName,
+				RefTableName: col.ReferencedTableName,
+				OnUpdate:     pgForeignKeyAction(col.UpdateRule),
+				OnDelete:     pgForeignKeyAction(col.DeleteRule),
+			})
+			n++
+		}
+		foreignKeys[n-1].Columns = append(foreignKeys[n-1].Columns, col.ColumnName)
+		foreignKeys[n-1].RefColumns = append(foreignKeys[n-1].RefColumns, col.ReferencedColumnName)
+	}
+	return foreignKeys, nil
+}
+
+//pgForeignKeyAction 将pg_constraint中的动作代码转换为SQL中的写法
+func pgForeignKeyAction(code string) string {
+	switch code {
+	case "r":
+		return "RESTRICT"
+	case "c":
+		return "CASCADE"
+	case "n":
+		return "SET NULL"
+	case "d":
+		return "SET DEFAULT"
+	default:
+		return "NO ACTION"
+	}
+}
+
+//pgIndexColumn pg_index中索引的一个字段
+type pgIndexColumn struct {
+	IndexName  string         `db:"index_name"`
+	IsUnique   bool           `db:"is_unique"`
+	IsPrimary  bool           `db:"is_primary"`
+	IndexType  string         `db:"index_type"`
+	ColumnName sql.NullString `db:"column_name"`
+}
+
+//GetIndexes 获取表的所有索引
+func (p *PostgresProvider) GetIndexes(ctx context.Context, tableSchema TableSchema) ([]Index, error) {
+	var cols []pgIndexColumn
+	err := p.db.SelectContext(ctx, &cols, `SELECT ic.relname AS index_name, i.indisunique AS is_unique, i.indisprimary AS is_primary,
+	am.amname AS index_type, a.attname AS column_name
+FROM pg_catalog.pg_index i
+JOIN pg_catalog.pg_class cl ON cl.oid = i.indrelid
+JOIN pg_catalog.pg_namespace n ON n.oid = cl.relnamespace
+JOIN pg_catalog.pg_class ic ON ic.oid = i.indexrelid
+JOIN pg_catalog.pg_am am ON am.oid = ic.relam
+CROSS JOIN LATERAL unnest(i.indkey::int2[]) WITH ORDINALITY AS k(attnum, ord)
+LEFT JOIN pg_catalog.pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = k.attnum AND k.attnum > 0
+WHERE n.nspname = $1 AND cl.relname = $2
+ORDER BY ic.relname, k.ord`, p.schema, tableSchema.TableName)
+	if err != nil {
+		return nil, err
+	}
+	var indexes []Index
+	for _, col := range cols {
+		n := len(indexes)
+		if n == 0 || indexes[n-1].Name != col.IndexName {
+			indexes = append(indexes, Index{
+				Name:      col.IndexName,
+				TableName: tableSchema.TableName,
+				IsPrimary: col.IsPrimary,
+				IsUnique:  col.IsUnique,
+				Type:      strings.ToUpper(col.IndexType),
+			})
+			n++
+		}
+		//表达式索引没有字段名
+		if !col.ColumnName.Valid {
+			continue
+		}
+		indexes[n-1].Columns = append(indexes[n-1].Columns, IndexColumn{Name: col.ColumnName.String})
+	}
+	return indexes, nil
+}
@@ -0,0 +1,682 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+//DefaultProtoTemplate 默认的proto文件模板
+const DefaultProtoTemplate = `syntax = "proto3";
+
+package {{.Package}};
+{{- if .Imports}}
+{{range .Imports}}
+import "{{.}}";
+{{- end}}
+{{- end}}
+{{- if .GoPackage}}
+
+option go_package = "{{.GoPackage}}";
+{{- end}}
+{{- range .Messages}}
+
+// {{.Name}} {{.Comment}}
+message {{.Name}} {
+{{- range .Enums}}
+  enum {{.Name}} {
+  {{- range .Values}}
+    {{.Name}} = {{.Number}};{{if .Value}} // {{.Value}}{{end}}
+  {{- end}}
+  }
+{{- end}}
+{{- range .Fields}}
+{{- if .Comment}}
+  // {{.Comment}}
+{{- end}}
+  {{.ProtoType}} {{.ProtoName}} = {{.Number}};
+{{- end}}
+}
+{{- end}}
+`
+
+//DefaultProtoConvertTemplate 默认的struct与protobuf message互相转换的方法模板
+const DefaultProtoConvertTemplate = `package {{.PackageName}}
+
+import (
+{{- range .Imports}}
+	{{.}}
+{{- end}}
+)
+
+//ToProto 将{{.StructName}}转换为protobuf的{{.Name}}
+func (t *{{.StructName}}) ToProto() *pb.{{.GoName}} {
+	m := &pb.{{.GoName}}{}
+{{- range .ToProto}}
+	{{.}}
+{{- end}}
+	return m
+}
+
+//FromProto 将protobuf的{{.Name}}转换为{{.StructName}}
+func (t *{{.StructName}}) FromProto(m *pb.{{.GoName}}) error {
+	if m == nil {
+		return nil
+	}
+{{- range .FromProto}}
+	{{.}}
+{{- end}}
+	return nil
+}
+`
+
+//protoTimestamp 时间类型对应的well-known type
+const protoTimestamp = "google.protobuf.Timestamp"
+
+//ProtoFile 渲染proto文件模板所需的数据
+type ProtoFile struct {
+	//Package proto的package
+	Package string
+	//GoPackage go_package选项
+	GoPackage string
+	//Imports 导入的proto文件
+	Imports []string
+	//Messages 每张表对应一个message
+	Messages []ProtoMessage
+}
+
+//ProtoMessage 表对应的message
+type ProtoMessage struct {
+	//Name message名称,与struct名称相同
+	Name string
+	//GoName protoc-gen-go生成的类型名
+	GoName string
+	//Comment 表注释
+	Comment string
+	//Fields 字段,按ORDINAL_POSITION编号
+	Fields []ProtoField
+	//Enums enum字段对应的proto enum
+	Enums []ProtoEnum
+	//PackageName struct所在的包名
+	PackageName string
+	//StructName struct名称
+	StructName string
+	//Imports 转换方法需要导入的包
+	Imports []string
+	//ToProto ToProto方法中的语句
+	ToProto []string
+	//FromProto FromProto方法中的语句
+	FromProto []string
+}
+
+//ProtoField message中的字段
+type ProtoField struct {
+	Field
+	//ProtoName proto中的字段名
+	ProtoName string
+	//ProtoType proto中的类型,如int64、google.protobuf.StringValue
+	ProtoType string
+	//Number 字段编号
+	Number int
+	//Enum enum字段对应的proto enum,其他字段为nil
+	Enum *ProtoEnum
+}
+
+//ProtoEnum 嵌套在message中的enum
+type ProtoEnum struct {
+	//Name enum名称
+	Name string
+	//Values 可选值,第一个为表示未指定的0
+	Values []ProtoEnumValue
+}
+
+//ProtoEnumValue enum的值
+type ProtoEnumValue struct {
+	//Name proto中的名称,如STATUS_ON
+	Name string
+	//Number 编号
+	Number int
+	//Value 数据库中的值,表示未指定的值为空
+	Value string
+}
+
+//protoWrappers 允许为空的标量类型对应的wrapper和wrapperspb中的构造函数
+var protoWrappers = map[string][2]string{
+	"int32":  {"google.protobuf.Int32Value", "Int32"},
+	"uint32": {"google.protobuf.UInt32Value", "UInt32"},
+	"int64":  {"google.protobuf.Int64Value", "Int64"},
+	"uint64": {"google.protobuf.UInt64Value", "UInt64"},
+	"float":  {"google.protobuf.FloatValue", "Float"},
+	"double": {"google.protobuf.DoubleValue", "Double"},
+	"bool":   {"google.protobuf.BoolValue", "Bool"},
+	"string": {"google.protobuf.StringValue", "String"},
+	"bytes":  {"google.protobuf.BytesValue", "Bytes"},
+}
+
+//protoScalarType 将数据库的数据类型转换为proto的类型,enum字段返回enum
+func protoScalarType(dbType string, field Field) string {
+	dataType := strings.ToLower(field.DataType)
+	if dbType == "sqlite" {
+		switch {
+		case dataType == "bool" || dataType == "boolean":
+			return "bool"
+		case dataType == "date" || dataType == "datetime" || dataType == "timestamp" || dataType == "time":
+			return protoTimestamp
+		case strings.Contains(dataType, "int"):
+			return "int64"
+		case strings.Contains(dataType, "char"), strings.Contains(dataType, "clob"), strings.Contains(dataType, "text"):
+			return "string"
+		case dataType == "", strings.Contains(dataType, "blob"):
+			return "bytes"
+		case strings.Contains(dataType, "real"), strings.Contains(dataType, "floa"), strings.Contains(dataType, "doub"):
+			return "double"
+		}
+		return "string"
+	}
+	switch dataType {
+	case "tinyint", "int1", "smallint", "mediumint", "int", "integer", "int2", "int3", "int4", "middleint", "year", "serial2", "serial4":
+		if field.IsUnsigned {
+			return "uint32"
+		}
+		return "int32"
+	case "bigint", "int8", "serial8", "oid":
+		if field.IsUnsigned {
+			return "uint64"
+		}
+		return "int64"
+	case "bit":
+		if dbType == "postgres" {
+			return "string"
+		}
+		if strings.ToLower(field.OriginType) == "bit(1)" {
+			return "bool"
+		}
+		return "uint64"
+	case "float", "float4":
+		return "float"
+	case "double", "real", "float8":
+		return "double"
+	case "decimal", "dec", "numeric", "fixed", "money":
+		//用字符串保存以免丢失精度
+		return "string"
+	case "bool", "boolean":
+		return "bool"
+	case "date", "datetime", "time", "timetz", "timestamp", "timestamptz":
+		return protoTimestamp
+	case "binary", "varbinary", "tinyblob", "blob", "mediumblob", "longblob", "vector", "bytea",
+		"geometry", "point", "linestring", "polygon", "multipoint", "multilinestring", "multipolygon", "geometrycollection", "geomcollection":
+		return "bytes"
+	case "enum":
+		if len(field.EnumValues) > 0 {
+			return "enum"
+		}
+	}
+	return "string"
+}
+
+//ProtoMessage 生成表对应的message
+func (g *Generator) ProtoMessage(table Table) ProtoMessage {
+	data := g.StructData(table)
+	message := ProtoMessage{
+		Name:        data.GoName,
+		GoName:      protoGoName(data.GoName),
+		Comment:     protoComment(data.Comment),
+		PackageName: data.PackageName,
+		StructName:  data.GoName,
+	}
+	dbType := g.dbType()
+	for i, field := range table.Fields {
+		protoField := ProtoField{
+			Field:     field,
+			ProtoName: protoFieldName(field.Name),
+			Number:    field.Position,
+		}
+		protoField.Comment = protoComment(field.Comment)
+		if protoField.Number <= 0 {
+			protoField.Number = i + 1
+		}
+		scalarType := protoScalarType(dbType, field)
+		switch {
+		case scalarType == "enum":
+			enum := protoEnum(data.Fields[i].GoName, protoField.ProtoName, field.EnumValues)
+			message.Enums = append(message.Enums, enum)
+			protoField.ProtoType = enum.Name
+			protoField.Enum = &enum
+		case field.EnableNull && scalarType != protoTimestamp:
+			protoField.ProtoType = protoWrappers[scalarType][0]
+		default:
+			protoField.ProtoType = scalarType
+		}
+		message.Fields = append(message.Fields, protoField)
+	}
+	return message
+}
+
+//protoEnum 根据enum的可选值生成proto enum,值的名称以字段名为前缀
+func protoEnum(name, fieldName string, values []string) ProtoEnum {
+	prefix := strings.ToUpper(fieldName) + "_"
+	enum := ProtoEnum{
+		Name:   name,
+		Values: []ProtoEnumValue{{Name: prefix + "UNSPECIFIED"}},
+	}
+	used := map[string]bool{enum.Values[0].Name: true}
+	for i, value := range values {
+		valueName := prefix + "EMPTY"
+		if value != "" {
+			valueName = prefix + protoIdentifier(strings.ToUpper(value), "VALUE")
+		}
+		for used[valueName] {
+			valueName += "_" + strconv.Itoa(i+1)
+		}
+		used[valueName] = true
+		enum.Values = append(enum.Values, ProtoEnumValue{Name: valueName, Number: i + 1, Value: value})
+	}
+	return enum
+}
+
+//protoIdentifier 将非字母、数字的字符替换为下划线,以数字开头或为空时加上前缀
+func protoIdentifier(s, prefix string) string {
+	runes := []rune(s)
+	for i, r := range runes {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_') {
+			runes[i] = '_'
+		}
+	}
+	s = string(runes)
+	if s == "" || s[0] >= '0' && s[0] <= '9' {
+		s = prefix + "_" + s
+	}
+	return s
+}
+
+//protoFieldName proto中的字段名,使用小写的字段名
+func protoFieldName(name string) string {
+	return protoIdentifier(strings.ToLower(name), "f")
+}
+
+//protoComment 注释中的换行替换为空格
+func protoComment(comment string) string {
+	return strings.Join(strings.Fields(comment), " ")
+}
+
+//protoGoName 与protoc-gen-go相同的命名规则,如user_id转换为UserId
+func protoGoName(s string) string {
+	var b []byte
+	isLower := func(c byte) bool { return c >= 'a' && c <= 'z' }
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '.' && i+1 < len(s) && isLower(s[i+1]):
+		case c == '.':
+			b = append(b, '_')
+		case c == '_' && (i == 0 || s[i-1] == '.'):
+			b = append(b, 'X')
+		case c == '_' && i+1 < len(s) && isLower(s[i+1]):
+		case c >= '0' && c <= '9':
+			b = append(b, c)
+		default:
+			if isLower(c) {
+				c -= 'a' - 'A'
+			}
+			b = append(b, c)
+			for ; i+1 < len(s) && isLower(s[i+1]); i++ {
+				b = append(b, s[i+1])
+			}
+		}
+	}
+	return string(b)
+}
+
+//ProtoFile 生成包含所有表的proto文件数据
+func (g *Generator) ProtoFile(tables []Table) ProtoFile {
+	file := ProtoFile{
+		Package:   g.protoPackage(),
+		GoPackage: g.opts.ProtoGoPackage,
+	}
+	var hasTimestamp, hasWrapper bool
+	for _, table := range tables {
+		message := g.ProtoMessage(table)
+		for _, field := range message.Fields {
+			hasTimestamp = hasTimestamp || field.ProtoType == protoTimestamp
+			hasWrapper = hasWrapper || strings.HasSuffix(field.ProtoType, "Value")
+		}
+		file.Messages = append(file.Messages, message)
+	}
+	if hasTimestamp {
+		file.Imports = append(file.Imports, "google/protobuf/timestamp.proto")
+	}
+	if hasWrapper {
+		file.Imports = append(file.Imports, "google/protobuf/wrappers.proto")
+	}
+	return file
+}
+
+//protoPackage proto的package,默认与包名相同
+func (g *Generator) protoPackage() string {
+	if g.opts.ProtoPackage != "" {
+		return g.opts.ProtoPackage
+	}
+	return g.opts.PackageName
+}
+
+//protoFileName proto文件名,如models.proto
+func (g *Generator) protoFileName() string {
+	return strings.Replace(g.protoPackage(), ".", "_", -1) + ".proto"
+}
+
+//RenderProto 生成包含所有表的proto文件
+func (g *Generator) RenderProto(tables []Table) ([]byte, error) {
+	tpl, err := template.New("proto").Funcs(g.templateFuncs()).Parse(DefaultProtoTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("解析模板失败:%v", err)
+	}
+	buf := bytes.NewBufferString("")
+	if err := tpl.Execute(buf, g.ProtoFile(tables)); err != nil {
+		return nil, fmt.Errorf("渲染proto文件失败:%v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+//RenderProtoConvert 生成表的ToProto和FromProto方法
+func (g *Generator) RenderProtoConvert(table Table) ([]byte, error) {
+	message := g.ProtoMessage(table)
+	converter := protoConverter{message: message.GoName, imports: map[string]bool{}}
+	data := g.StructData(table)
+	for i, field := range message.Fields {
+		toProto, fromProto, ok := converter.convert(data.Fields[i].GoName, field)
+		if !ok {
+			g.warnf("表%s的字段%s: 类型%s无法与protobuf的%s互相转换,ToProto和FromProto中已忽略", table.Name, field.Name, field.Type, field.ProtoType)
+			continue
+		}
+		message.ToProto = append(message.ToProto, toProto...)
+		message.FromProto = append(message.FromProto, fromProto...)
+	}
+	goPackage := g.opts.ProtoGoPackage
+	if i := strings.Index(goPackage, ";"); i >= 0 {
+		goPackage = goPackage[:i]
+	}
+	message.Imports = []string{`pb "` + goPackage + `"`}
+	for spec := range converter.imports {
+		message.Imports = append(message.Imports, spec)
+	}
+	sort.Strings(message.Imports)
+
+	tpl, err := template.New("proto_convert").Funcs(g.templateFuncs()).Parse(DefaultProtoConvertTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("解析模板失败:%v", err)
+	}
+	buf := bytes.NewBufferString("")
+	if err := tpl.Execute(buf, message); err != nil {
+		return nil, fmt.Errorf("渲染表%s的ToProto和FromProto失败:%v", table.Name, err)
+	}
+	content, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("格式化失败:%v", err)
+	}
+	return content, nil
+}
+
+//protoConverter 生成struct字段与message字段互相转换的语句
+type protoConverter struct {
+	//message protoc-gen-go生成的message类型名
+	message string
+	//imports 转换时用到的包
+	imports map[string]bool
+}
+
+//structAccess struct字段的读写方式
+type structAccess struct {
+	//valid 判断是否为NULL的表达式,为空时表示总是有值
+	valid string
+	//get 读取值的表达式
+	get string
+	//baseType 值的类型
+	baseType string
+	//pointer 是否为指针
+	pointer bool
+	//assign 将变量v的值写入字段的语句
+	assign []string
+}
+
+//structNullMembers sql.NullString等类型中保存值的字段,gopkg.in/guregu/null.v4中的类型内嵌了这些类型
+var structNullMembers = map[string][2]string{
+	"sql.NullString":  {"String", "string"},
+	"sql.NullInt64":   {"Int64", "int64"},
+	"sql.NullFloat64": {"Float64", "float64"},
+	"sql.NullBool":    {"Bool", "bool"},
+	"sql.NullTime":    {"Time", "time.Time"},
+	"null.String":     {"String", "string"},
+	"null.Int":        {"Int64", "int64"},
+	"null.Float":      {"Float64", "float64"},
+	"null.Bool":       {"Bool", "bool"},
+	"null.Time":       {"Time", "time.Time"},
+}
+
+//structFieldAccess 根据字段类型确定读写方式,不支持的类型返回false
+func structFieldAccess(goName string, field Field) (structAccess, bool) {
+	name := "t." + goName
+	switch {
+	case strings.HasPrefix(field.Type, "*"):
+		return structAccess{
+			valid:    name + " != nil",
+			get:      "*" + name,
+			baseType: field.Type[1:],
+			pointer:  true,
+			assign:   []string{name + " = &v"},
+		}, true
+	case strings.HasPrefix(field.Type, "sql.Null[") && strings.HasSuffix(field.Type, "]"):
+		return structAccess{
+			valid:    name + ".Valid",
+			get:      name + ".V",
+			baseType: field.Type[len("sql.Null[") : len(field.Type)-1],
+			assign:   []string{name + ".V = v", name + ".Valid = true"},
+		}, true
+	}
+	if member, ok := structNullMembers[field.Type]; ok {
+		return structAccess{
+			valid:    name + ".Valid",
+			get:      name + "." + member[0],
+			baseType: member[1],
+			assign:   []string{name + "." + member[0] + " = v", name + ".Valid = true"},
+		}, true
+	}
+	if strings.Contains(field.Type, ".") && field.Type != "time.Time" {
+		return structAccess{}, false
+	}
+	access := structAccess{get: name, baseType: field.Type, assign: []string{name + " = v"}}
+	if field.Type == "[]byte" && field.EnableNull {
+		access.valid = name + " != nil"
+	}
+	return access, true
+}
+
+//protoGoTypes proto的标量类型对应的golang类型
+var protoGoTypes = map[string]string{
+	"int32":  "int32",
+	"uint32": "uint32",
+	"int64":  "int64",
+	"uint64": "uint64",
+	"float":  "float32",
+	"double": "float64",
+	"bool":   "bool",
+	"string": "string",
+	"bytes":  "[]byte",
+}
+
+func isGoNumberType(goType string) bool {
+	switch goType {
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "float32", "float64":
+		return true
+	}
+	return false
+}
+
+//convert 生成单个字段在ToProto和FromProto中的语句
+func (c *protoConverter) convert(goName string, field ProtoField) (toProto, fromProto []string, ok bool) {
+	access, ok := structFieldAccess(goName, field.Field)
+	if !ok {
+		return nil, nil, false
+	}
+	target := "m." + protoGoName(field.ProtoName)
+	if field.Enum != nil {
+		return c.convertEnum(target, access, field)
+	}
+	scalarType := field.ProtoType
+	wrapper := ""
+	for t, w := range protoWrappers {
+		if w[0] == field.ProtoType {
+			scalarType, wrapper = t, w[1]
+		}
+	}
+	protoGoType := protoGoTypes[scalarType]
+
+	//to将struct中的值转换为proto的类型,from将proto中的值转换为struct的类型,fromStmts用于需要检查错误的转换
+	var to func(x string) string
+	var from func(src string) string
+	var fromStmts func(src string) []string
+	cast := func(goType string) func(string) string {
+		return func(x string) string { return goType + "(" + x + ")" }
+	}
+	same := func(x string) string { return x }
+	baseType := access.baseType
+	switch {
+	case scalarType == protoTimestamp && baseType == "time.Time":
+		c.imports[`"google.golang.org/protobuf/types/known/timestamppb"`] = true
+		to = func(x string) string { return "timestamppb.New(" + x + ")" }
+		from = func(src string) string { return src + ".AsTime()" }
+	case protoGoType == "":
+		return nil, nil, false
+	case baseType == protoGoType:
+		to, from = same, same
+	case isGoNumberType(baseType) && isGoNumberType(protoGoType):
+		to, from = cast(protoGoType), cast(baseType)
+	case baseType == "[]byte" && protoGoType == "string", baseType == "string" && protoGoType == "[]byte":
+		to, from = cast(protoGoType), cast(baseType)
+	case (baseType == "float64" || baseType == "float32") && protoGoType == "string":
+		//decimal
+		c.imports[`"fmt"`] = true
+		c.imports[`"strconv"`] = true
+		bitSize := baseType[len("float"):]
+		to = func(x string) string {
+			if baseType == "float32" {
+				x = "float64(" + x + ")"
+			}
+			return "strconv.FormatFloat(" + x + ", 'f', -1, " + bitSize + ")"
+		}
+		fromStmts = func(src string) []string {
+			result := "v"
+			if baseType == "float32" {
+				result = "f"
+			}
+			stmts := []string{
+				result + ", err := strconv.ParseFloat(" + src + ", " + bitSize + ")",
+				"if err != nil {",
+				fmt.Sprintf(`return fmt.Errorf("转换字段%s失败:%%v", err)`, field.ProtoName),
+				"}",
+			}
+			if baseType == "float32" {
+				stmts = append(stmts, "v := float32(f)")
+			}
+			return stmts
+		}
+	case field.IsSet && len(field.EnumValues) > 0 && protoGoType == "string":
+		//set类型
+		c.imports[`"fmt"`] = true
+		to = func(x string) string {
+			if strings.HasPrefix(x, "*") {
+				x = "(" + x + ")"
+			}
+			return x + ".String()"
+		}
+		fromStmts = func(src string) []string {
+			return []string{
+				"var v " + baseType,
+				"if err := v.Scan(" + src + "); err != nil {",
+				fmt.Sprintf(`return fmt.Errorf("转换字段%s失败:%%v", err)`, field.ProtoName),
+				"}",
+			}
+		}
+	default:
+		return nil, nil, false
+	}
+
+	//ToProto
+	value := to(access.get)
+	if wrapper != "" {
+		c.imports[`"google.golang.org/protobuf/types/known/wrapperspb"`] = true
+		value = "wrapperspb." + wrapper + "(" + value + ")"
+	}
+	toProto = guard(access.valid, []string{target + " = " + value})
+
+	//FromProto
+	src, valid := target, ""
+	switch {
+	case wrapper != "":
+		src, valid = target+".GetValue()", target+" != nil"
+	case scalarType == protoTimestamp:
+		valid = target + " != nil"
+	case fromStmts != nil && protoGoType == "string":
+		valid = target + ` != ""`
+	}
+	if fromStmts == nil && !access.pointer && len(access.assign) == 1 {
+		fromProto = guard(valid, []string{strings.Replace(access.assign[0], "= v", "= "+from(src), 1)})
+		return toProto, fromProto, true
+	}
+	var stmts []string
+	if fromStmts != nil {
+		stmts = fromStmts(src)
+	} else {
+		stmts = []string{"v := " + from(src)}
+	}
+	if valid == "" {
+		return toProto, append(append([]string{"{"}, append(stmts, access.assign...)...), "}"), true
+	}
+	return toProto, guard(valid, append(stmts, access.assign...)), true
+}
+
+//convertEnum enum字段按数据库中的值与proto enum互相转换,
+//FromProto中未知的值返回错误,不允许为空的字段为UNSPECIFIED时也返回错误,允许为空的字段则保持为NULL
+func (c *protoConverter) convertEnum(target string, access structAccess, field ProtoField) (toProto, fromProto []string, ok bool) {
+	if field.IsSet || isGoNumberType(access.baseType) {
+		return nil, nil, false
+	}
+	var toCases []string
+	fromProto = []string{"switch " + target + " {"}
+	for _, value := range field.Enum.Values[1:] {
+		//嵌套的enum的值以message名为前缀
+		constant := "pb." + c.message + "_" + value.Name
+		literal := strconv.Quote(value.Value)
+		toCases = append(toCases, "case "+literal+":", target+" = "+constant)
+		fromProto = append(fromProto, "case "+constant+":")
+		if access.pointer {
+			if access.baseType != "string" {
+				literal = access.baseType + "(" + literal + ")"
+			}
+			fromProto = append(fromProto, "v := "+literal)
+			fromProto = append(fromProto, access.assign...)
+		} else {
+			for _, assign := range access.assign {
+				fromProto = append(fromProto, strings.Replace(assign, "= v", "= "+literal, 1))
+			}
+		}
+	}
+	if field.EnableNull {
+		fromProto = append(fromProto, "case pb."+c.message+"_"+field.Enum.Values[0].Name+":")
+	}
+	c.imports[`"fmt"`] = true
+	fromProto = append(fromProto, "default:", fmt.Sprintf(`return fmt.Errorf("字段%s的值%%v无效", %s)`, field.ProtoName, target), "}")
+	toProto = append(append([]string{"switch " + access.get + " {"}, toCases...), "}")
+	return guard(access.valid, toProto), fromProto, true
+}
+
+//guard 有条件时将语句放在if中
+func guard(condition string, stmts []string) []string {
+	if condition == "" {
+		return stmts
+	}
+	return append(append([]string{"if " + condition + " {"}, stmts...), "}")
+}
@@ -0,0 +1,197 @@
+package generator
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+//protoTestDDL 覆盖各种转换方式的表,testdata/proto中的proto文件和protoc-gen-go生成的代码都来自这张表
+const protoTestDDL = `CREATE TABLE item (
+  id bigint unsigned NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  name varchar(50) NOT NULL COMMENT '名称',
+  title varchar(100) DEFAULT NULL,
+  status enum('on','off','') NOT NULL DEFAULT 'on' COMMENT '状态',
+  kind enum('a','b') DEFAULT NULL,
+  flags set('x','y') NOT NULL DEFAULT '',
+  price decimal(10,2) NOT NULL,
+  weight float DEFAULT NULL,
+  amount int DEFAULT NULL,
+  data blob,
+  created_at datetime NOT NULL,
+  deleted_at datetime DEFAULT NULL,
+  PRIMARY KEY (id)
+) COMMENT='商品';`
+
+//protoTestGoPackage testdata/proto/pb在临时模块中的导入路径
+const protoTestGoPackage = "example.com/protocheck/pb"
+
+//protoTestConvert 在临时模块中运行的测试,检查ToProto和FromProto能还原message,并且拒绝无效的enum值
+const protoTestConvert = `package PACKAGE
+
+import (
+	"testing"
+	"time"
+
+	pb "example.com/protocheck/pb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func newMessage() *pb.Item {
+	return &pb.Item{
+		Id:        1,
+		Name:      "name",
+		Title:     wrapperspb.String("title"),
+		Status:    pb.Item_STATUS_OFF,
+		Kind:      pb.Item_KIND_B,
+		Flags:     "x,y",
+		Price:     "12.5",
+		Weight:    wrapperspb.Float(1.5),
+		Amount:    wrapperspb.Int32(-3),
+		Data:      wrapperspb.Bytes([]byte("data")),
+		CreatedAt: timestamppb.New(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)),
+		DeletedAt: timestamppb.New(time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)),
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	m := newMessage()
+	var item Item
+	if err := item.FromProto(m); err != nil {
+		t.Fatal(err)
+	}
+	if got := item.ToProto(); !proto.Equal(got, m) {
+		t.Fatalf("got %v, want %v", got, m)
+	}
+	m.Status = pb.Item_STATUS_EMPTY
+	if err := item.FromProto(m); err != nil {
+		t.Fatal(err)
+	}
+	if got := item.ToProto(); got.Status != pb.Item_STATUS_EMPTY {
+		t.Fatalf("status: got %v, want STATUS_EMPTY", got.Status)
+	}
+}
+
+func TestInvalidEnum(t *testing.T) {
+	for _, status := range []pb.Item_Status{pb.Item_STATUS_UNSPECIFIED, 100} {
+		m := newMessage()
+		m.Status = status
+		var item Item
+		if err := item.FromProto(m); err == nil {
+			t.Errorf("status %v: expected error", status)
+		}
+	}
+	m := newMessage()
+	m.Kind = 100
+	var item Item
+	if err := item.FromProto(m); err == nil {
+		t.Error("kind 100: expected error")
+	}
+	//允许为空的字段为UNSPECIFIED时为NULL
+	m.Kind = pb.Item_KIND_UNSPECIFIED
+	if err := item.FromProto(m); err != nil {
+		t.Fatal(err)
+	}
+	if got := item.ToProto(); got.Kind != pb.Item_KIND_UNSPECIFIED {
+		t.Fatalf("kind: got %v, want KIND_UNSPECIFIED", got.Kind)
+	}
+}
+`
+
+//TestProtoConvertCompiles 用testdata/proto/pb中protoc-gen-go生成的代码编译并运行各种null_strategy下生成的ToProto和FromProto。
+//修改了proto的生成规则后需要在testdata/proto中执行protoc --go_out=paths=source_relative:pb models.proto重新生成
+func TestProtoConvertCompiles(t *testing.T) {
+	goCmd, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("找不到go命令")
+	}
+	ddlSchema, err := ParseDDL(protoTestDDL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("", "protocheck")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	goMod := "module example.com/protocheck\n\ngo 1.23\n\nrequire google.golang.org/protobuf v1.36.12\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0644); err != nil {
+		t.Fatal(err)
+	}
+	pbCode, err := ioutil.ReadFile(filepath.Join("testdata", "proto", "pb", "models.pb.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dir, "pb", "models.pb.go"), pbCode)
+	wantProto, err := ioutil.ReadFile(filepath.Join("testdata", "proto", "models.proto"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	variants := []struct {
+		pkg     string
+		options func(opts *Options)
+	}{
+		{"plain", func(opts *Options) {}},
+		{"sqlnull", func(opts *Options) { opts.NullStrategy = NullStrategySQL; opts.Enums = true }},
+		{"pointer", func(opts *Options) { opts.NullStrategy = NullStrategyPointer; opts.Enums = true }},
+		{"generic", func(opts *Options) { opts.NullStrategy = NullStrategyGeneric }},
+	}
+	for _, variant := range variants {
+		opts := DefaultOptions()
+		opts.Provider = ddlSchema
+		opts.PackageName = variant.pkg
+		opts.ProtoPackage = "models"
+		opts.ProtoConvert = true
+		opts.ProtoGoPackage = protoTestGoPackage
+		variant.options(&opts)
+		g, err := New(opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files, err := g.Generate(context.Background())
+		if err != nil {
+			t.Fatalf("%s: %v", variant.pkg, err)
+		}
+		if warnings := g.Warnings(); len(warnings) > 0 {
+			t.Errorf("%s: %v", variant.pkg, warnings)
+		}
+		for _, file := range files {
+			if strings.HasSuffix(file.Name, ".proto") {
+				if string(file.Content) != string(wantProto) {
+					t.Fatalf("%s: 生成的proto文件与testdata/proto/models.proto不一致,需要重新生成testdata:\n%s", variant.pkg, file.Content)
+				}
+				continue
+			}
+			writeFile(t, filepath.Join(dir, variant.pkg, file.Name), file.Content)
+		}
+		writeFile(t, filepath.Join(dir, variant.pkg, "convert_test.go"), []byte(strings.Replace(protoTestConvert, "PACKAGE", variant.pkg, 1)))
+	}
+
+	env := append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off", "GOSUMDB=off")
+	download := exec.Command(goCmd, "mod", "download", "google.golang.org/protobuf")
+	download.Dir, download.Env = dir, env
+	if output, err := download.CombinedOutput(); err != nil {
+		t.Skipf("无法下载google.golang.org/protobuf:%v\n%s", err, output)
+	}
+	test := exec.Command(goCmd, "test", "./...")
+	test.Dir, test.Env = dir, env
+	if output, err := test.CombinedOutput(); err != nil {
+		t.Fatalf("%v\n%s", err, output)
+	}
+}
+
+func writeFile(t *testing.T, path string, content []byte) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,15 @@
+package generator
+
+import "context"
+
+//Provider 表结构来源
+type Provider interface {
+	//GetTables 获取所有表,args不为空时只返回指定的表
+	GetTables(ctx context.Context, args []string) ([]TableSchema, error)
+	//GetColumns 获取表的所有字段
+	GetColumns(ctx context.Context, tableSchema TableSchema) ([]ColumnSchema, error)
+	//ParseField 解析字段
+	ParseField(col ColumnSchema, opts *Options) Field
+	//GoType 将数据库类型转换为golang类型,无法识别的类型返回空字符串
+	GoType(dbType string, isNullAble bool, opts *Options) (goType string, isNullType bool, isExtNullType bool)
+}
@@ -0,0 +1,244 @@
+package generator
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+//ForeignKey 外键
+type ForeignKey struct {
+	//Name 外键名称
+	Name string `json:"name"`
+	//TableName 外键所在的表
+	TableName string `json:"-"`
+	//Columns 外键字段
+	Columns []string `json:"columns"`
+	//RefTableName 引用的表
+	RefTableName string `json:"ref_table"`
+	//RefColumns 引用的字段
+	RefColumns []string `json:"ref_columns"`
+	//OnUpdate 更新时的动作,如CASCADE
+	OnUpdate string `json:"on_update,omitempty"`
+	//OnDelete 删除时的动作,如CASCADE
+	OnDelete string `json:"on_delete,omitempty"`
+}
+
+//ForeignKeyProvider 能够读取外键的表结构来源
+type ForeignKeyProvider interface {
+	//GetForeignKeys 获取所有外键
+	GetForeignKeys(ctx context.Context) ([]ForeignKey, error)
+}
+
+//Association 关联字段
+type Association struct {
+	//GoName golang字段名
+	GoName string
+	//Type 字段类型,belongs-to为指针,has-many为切片
+	Type string
+	//IsBelongsTo 是否为belongs-to关联,否则为has-many
+	IsBelongsTo bool
+	//ForeignKey 对应的外键
+	ForeignKey ForeignKey
+	//Tag 完整的tag
+	Tag string
+	//Tags 按顺序排列的各个tag
+	Tags []StructTag
+}
+
+//loadForeignKeys 读取外键并缓存,Provider不支持外键时返回空
+func (g *Generator) loadForeignKeys(ctx context.Context, provider Provider) ([]ForeignKey, error) {
+	if g.foreignKeysLoaded {
+		return g.foreignKeys, nil
+	}
+	if fkProvider, ok := provider.(ForeignKeyProvider); ok {
+		foreignKeys, err := fkProvider.GetForeignKeys(ctx)
+		if err != nil {
+			return nil, err
+		}
+		g.foreignKeys = foreignKeys
+	}
+	g.foreignKeysLoaded = true
+	return g.foreignKeys, nil
+}
+
+//StructName 根据数据库中的表名获取struct名称
+func (g *Generator) StructName(originTableName string) string {
+	if tableOptions, ok := g.opts.TableOptions[originTableName]; ok && tableOptions.StructName != "" {
+		return tableOptions.StructName
+	}
+	name := g.trimTablePrefix(originTableName)
+	return g.GoName(name, name)
+}
+
+//structNames 本次生成的所有struct名称
+func (g *Generator) structNames() map[string]bool {
+	names := make(map[string]bool, len(g.tables))
+	for tableName := range g.tables {
+		names[g.StructName(tableName)] = true
+	}
+	return names
+}
+
+//isGenerating 表是否在本次生成的表中,直接调用RenderTable时总是返回true
+func (g *Generator) isGenerating(originTableName string) bool {
+	return g.tables == nil || g.tables[originTableName]
+}
+
+//trimTablePrefix 去掉表名前缀
+func (g *Generator) trimTablePrefix(tableName string) string {
+	if g.opts.TablePrefix != "" && strings.HasPrefix(tableName, g.opts.TablePrefix) {
+		return tableName[len(g.opts.TablePrefix):]
+	}
+	return tableName
+}
+
+//columnsGoNames 将字段名转换为golang字段名,用逗号连接
+func (g *Generator) columnsGoNames(columns []string, originTableName string) string {
+	tableName := g.trimTablePrefix(originTableName)
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		names = append(names, g.GoName(column, tableName))
+	}
+	return strings.Join(names, ",")
+}
+
+//associations 根据外键生成关联字段,关联的表不在本次生成的表中时跳过并输出警告
+func (g *Generator) associations(table Table, fieldNames map[string]bool) []Association {
+	associations := make([]Association, 0, len(table.ForeignKeys)+len(table.ReferencedBy))
+	used := make(map[string]bool, len(fieldNames))
+	for name := range fieldNames {
+		used[name] = true
+	}
+	uniqueName := func(name, fallback string) string {
+		if !used[name] {
+			used[name] = true
+			return name
+		}
+		name = fallback
+		for i := 2; used[name]; i++ {
+			name = fallback + strconv.Itoa(i)
+		}
+		used[name] = true
+		return name
+	}
+	for _, fk := range table.ForeignKeys {
+		if !g.isGenerating(fk.RefTableName) {
+			g.warnf("表%s: 外键%s引用的表%s不在本次生成的表中,已跳过关联字段", table.Name, fk.Name, fk.RefTableName)
+			continue
+		}
+		refStruct := g.StructName(fk.RefTableName)
+		name := refStruct
+		if len(fk.Columns) == 1 {
+			column := fk.Columns[0]
+			if strings.HasSuffix(strings.ToLower(column), "_id") && len(column) > 3 {
+				name = g.GoName(column[:len(column)-3], table.Name)
+			}
+		}
+		association := Association{
+			GoName:      uniqueName(name, name+refStruct),
+			Type:        "*" + refStruct,
+			IsBelongsTo: true,
+			ForeignKey:  fk,
+		}
+		association.Tags = g.associationTags(association, g.columnsGoNames(fk.Columns, fk.TableName), g.columnsGoNames(fk.RefColumns, fk.RefTableName))
+		associations = append(associations, association)
+	}
+	for _, fk := range table.ReferencedBy {
+		if !g.isGenerating(fk.TableName) {
+			g.warnf("表%s: 表%s的外键%s不在本次生成的表中,已跳过关联字段", table.Name, fk.TableName, fk.Name)
+			continue
+		}
+		refStruct := g.StructName(fk.TableName)
+		name := pluralize(refStruct)
+		fallback := name
+		if len(fk.Columns) == 1 {
+			column := fk.Columns[0]
+			if strings.HasSuffix(strings.ToLower(column), "_id") && len(column) > 3 {
+				fallback = g.GoName(column[:len(column)-3], g.trimTablePrefix(fk.TableName)) + name
+			}
+		}
+		association := Association{
+			GoName:     uniqueName(name, fallback),
+			Type:       "[]" + refStruct,
+			ForeignKey: fk,
+		}
+		association.Tags = g.associationTags(association, g.columnsGoNames(fk.Columns, fk.TableName), g.columnsGoNames(fk.RefColumns, fk.RefTableName))
+		associations = append(associations, association)
+	}
+	for i := range associations {
+		associations[i].Tag = joinTags(associations[i].Tags)
+	}
+	return associations
+}
+
+//associationTags 生成关联字段的tag
+func (g *Generator) associationTags(association Association, foreignKey, references string) []StructTag {
+	tags := make([]StructTag, 0)
+	if g.opts.TagJSON {
+		tags = append(tags, StructTag{Key: "json", Value: toSnakeName(association.GoName) + ",omitempty"})
+	}
+	if g.opts.TagSQLX {
+		tags = append(tags, StructTag{Key: "db", Value: "-"})
+	}
+	if g.opts.TagGORM {
+		value := "foreignKey:" + foreignKey + ";references:" + references
+		if constraint := gormConstraintTag(association.ForeignKey); constraint != "" {
+			value += ";constraint:" + constraint
+		}
+		tags = append(tags, StructTag{Key: "gorm", Value: value})
+	}
+	if g.opts.TagXORM {
+		tags = append(tags, StructTag{Key: "xorm", Value: "-"})
+	}
+	return tags
+}
+
+//gormConstraintTag 外键的ON UPDATE、ON DELETE对应的gorm设置,如OnDelete:CASCADE,默认的动作不生成
+func gormConstraintTag(fk ForeignKey) string {
+	var items []string
+	if fk.OnUpdate != "" && fk.OnUpdate != "NO ACTION" && fk.OnUpdate != "RESTRICT" {
+		items = append(items, "OnUpdate:"+fk.OnUpdate)
+	}
+	if fk.OnDelete != "" && fk.OnDelete != "NO ACTION" && fk.OnDelete != "RESTRICT" {
+		items = append(items, "OnDelete:"+fk.OnDelete)
+	}
+	return strings.Join(items, ",")
+}
+
+//pluralize 将单数形式的英文名转换为复数形式,已经是复数形式(以s结尾)的名称保持不变
+func pluralize(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, "s") && !strings.HasSuffix(lower, "ss"):
+		return name
+	case strings.HasSuffix(lower, "ss"), strings.HasSuffix(lower, "x"), strings.HasSuffix(lower, "z"),
+		strings.HasSuffix(lower, "ch"), strings.HasSuffix(lower, "sh"):
+		return name + "es"
+	case strings.HasSuffix(lower, "y") && len(lower) > 1 && !strings.ContainsRune("aeiou", rune(lower[len(lower)-2])):
+		return name[:len(name)-1] + "ies"
+	}
+	return name + "s"
+}
+
+//toSnakeName 将golang名称转换为下划线形式,如UserID转换为user_id
+func toSnakeName(name string) string {
+	var buf strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		isUpper := r >= 'A' && r <= 'Z'
+		if isUpper && i > 0 {
+			prevLower := runes[i-1] >= 'a' && runes[i-1] <= 'z'
+			nextLower := i+1 < len(runes) && runes[i+1] >= 'a' && runes[i+1] <= 'z'
+			prevUpper := runes[i-1] >= 'A' && runes[i-1] <= 'Z'
+			if prevLower || (prevUpper && nextLower) {
+				buf.WriteByte('_')
+			}
+		}
+		if isUpper {
+			r += 'a' - 'A'
+		}
+		buf.WriteRune(r)
+	}
+	return buf.String()
+}
@@ -0,0 +1,487 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"strings"
+	"text/template"
+)
+
+//DefaultTemplate 默认的struct模板
+const DefaultTemplate = `package {{.PackageName}}
+{{if .Imports}}
+import (
+{{- range .Imports}}
+	{{.}}
+{{- end}}
+)
+{{end}}
+//{{.GoName}} {{.Comment}}
+type {{.GoName}} struct {
+{{- range .Fields}}
+{{- if .Comment}}
+	//{{.GoName}} {{.Comment}}
+{{- end}}
+	{{.GoName}} {{.Type}}{{if .Tag}} ` + "`{{.Tag}}`" + `{{end}}
+{{- end}}
+{{- range .Associations}}
+	{{.GoName}} {{.Type}}{{if .Tag}} ` + "`{{.Tag}}`" + `{{end}}
+{{- end}}
+}
+
+//TableName {{.TableName}}
+func (t {{.GoName}}) TableName() string {
+	return "{{.TableName}}"
+}
+{{- if .IndexesMethod}}
+
+//Indexes {{.TableName}}的索引
+func (t {{.GoName}}) Indexes() []struct {
+	Name    string
+	Primary bool
+	Unique  bool
+	Columns []string
+} {
+	return []struct {
+		Name    string
+		Primary bool
+		Unique  bool
+		Columns []string
+	}{
+	{{- range .Indexes}}
+		{Name: {{printf "%q" .Name}}, Primary: {{.IsPrimary}}, Unique: {{.IsUnique}}, Columns: {{printf "%#v" .ColumnDefs}}},
+	{{- end}}
+	}
+}
+{{- end}}
+{{- if .ColumnsVar}}
+
+//{{.ColumnsVar}} {{.TableName}}的表名和字段名
+var {{.ColumnsVar}} = struct {
+	{{.ColumnsTableField}} string
+{{- range .Fields}}
+	{{.GoName}} string
+{{- end}}
+}{
+	{{.ColumnsTableField}}: {{printf "%q" .TableName}},
+{{- range .Fields}}
+	{{.GoName}}: {{printf "%q" .Name}},
+{{- end}}
+}
+{{- end}}
+{{- if .ColumnsMethod}}
+
+//Columns {{.TableName}}的所有字段名
+func (t {{.GoName}}) Columns() []string {
+	return []string{ {{- range $i, $f := .Fields}}{{if $i}}, {{end}}{{printf "%q" .Name}}{{end -}} }
+}
+{{- end}}
+{{- if .FieldByColumnMethod}}
+
+//FieldByColumn 根据字段名获取字段的指针,可以用于Scan,字段不存在时返回false
+func (t *{{.GoName}}) FieldByColumn(column string) (interface{}, bool) {
+	switch column {
+{{- range .Fields}}
+	case {{printf "%q" .Name}}:
+		return &t.{{.GoName}}, true
+{{- end}}
+	}
+	return nil, false
+}
+{{- end}}
+{{- range .Enums}}
+{{- $type := .GoName}}
+
+//{{.GoName}} {{.Comment}}
+{{- if .IsSet}}
+type {{.GoName}} uint64
+
+const (
+{{- range $i, $v := .Values}}
+	//{{.GoName}} {{.Value}}
+	{{.GoName}}{{if eq $i 0}} {{$type}} = 1 << iota{{end}}
+{{- end}}
+)
+
+var {{.ValuesVar}} = []string{ {{- range $i, $v := .Values}}{{if $i}}, {{end}}{{printf "%q" .Value}}{{end -}} }
+
+//Has 是否包含v中的所有值
+func (s {{.GoName}}) Has(v {{.GoName}}) bool {
+	return s&v == v
+}
+
+//String 返回以逗号分隔的值
+func (s {{.GoName}}) String() string {
+	var values []string
+	for i, value := range {{.ValuesVar}} {
+		if s&(1<<uint(i)) != 0 {
+			values = append(values, value)
+		}
+	}
+	return strings.Join(values, ",")
+}
+
+//IsValid 是否只包含合法的值
+func (s {{.GoName}}) IsValid() bool {
+	return s>>uint(len({{.ValuesVar}})) == 0
+}
+
+//Scan 实现sql.Scanner接口
+func (s *{{.GoName}}) Scan(value interface{}) error {
+	var str string
+	switch v := value.(type) {
+	case nil:
+		*s = 0
+		return nil
+	case int64:
+		*s = {{.GoName}}(v)
+		return nil
+	case []byte:
+		str = string(v)
+	case string:
+		str = v
+	default:
+		return fmt.Errorf("无法将%T转换为{{.GoName}}", value)
+	}
+	*s = 0
+	if str == "" {
+		return nil
+	}
+	for _, item := range strings.Split(str, ",") {
+		found := false
+		for i, value := range {{.ValuesVar}} {
+			if item == value {
+				*s |= 1 << uint(i)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%q不是{{.GoName}}的合法值", item)
+		}
+	}
+	return nil
+}
+
+//Value 实现driver.Valuer接口
+func (s {{.GoName}}) Value() (driver.Value, error) {
+	return s.String(), nil
+}
+{{- else}}
+type {{.GoName}} string
+
+const (
+{{- range .Values}}
+	//{{.GoName}} {{.Value}}
+	{{.GoName}} {{$type}} = {{printf "%q" .Value}}
+{{- end}}
+)
+
+//String 返回字符串形式的值
+func (e {{.GoName}}) String() string {
+	return string(e)
+}
+
+//IsValid 是否为合法的值
+func (e {{.GoName}}) IsValid() bool {
+	switch e {
+	case {{range $i, $v := .Values}}{{if $i}}, {{end}}{{.GoName}}{{end}}:
+		return true
+	}
+	return false
+}
+
+//Scan 实现sql.Scanner接口
+func (e *{{.GoName}}) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*e = ""
+	case []byte:
+		*e = {{.GoName}}(v)
+	case string:
+		*e = {{.GoName}}(v)
+	default:
+		return fmt.Errorf("无法将%T转换为{{.GoName}}", value)
+	}
+	return nil
+}
+
+//Value 实现driver.Valuer接口
+func (e {{.GoName}}) Value() (driver.Value, error) {
+	return string(e), nil
+}
+{{- end}}
+{{- end}}
+`
+
+//StructData 渲染struct模板时的数据
+type StructData struct {
+	//PackageName 包名
+	PackageName string
+	//Imports 需要导入的包,如"time"、nulltype "github.com/mattn/go-nulltype"
+	Imports []string
+	//GoName struct名称
+	GoName string
+	//TableName 数据库中的表名(包含前缀)
+	TableName string
+	//Comment 表注释,没有注释时为表名
+	Comment string
+	//Fields 字段
+	Fields []StructField
+	//Associations 根据外键生成的关联字段
+	Associations []Association
+	//Indexes 表的索引,未开启Indexes选项时为空
+	Indexes []Index
+	//Enums enum和set字段生成的类型,未开启Enums选项时为空
+	Enums []EnumType
+	//ColumnsVar 保存表名和字段名的变量名,如UserColumns,未开启Columns选项时为空
+	ColumnsVar string
+	//ColumnsTableField ColumnsVar中保存表名的字段,一般为Table,与字段名冲突时在后面加下划线
+	ColumnsTableField string
+	//IndexesMethod 是否生成Indexes方法,没有索引或与字段名冲突时为false
+	IndexesMethod bool
+	//ColumnsMethod 是否生成Columns方法,未开启Columns选项或与字段名冲突时为false
+	ColumnsMethod bool
+	//FieldByColumnMethod 是否生成FieldByColumn方法,未开启Columns选项或与字段名冲突时为false
+	FieldByColumnMethod bool
+	//Table 表
+	Table Table
+	//Schema information_schema中的表信息
+	Schema TableSchema
+}
+
+//StructField 渲染struct模板时的字段数据
+type StructField struct {
+	Field
+	//GoName golang字段名
+	GoName string
+	//Tag 完整的tag,如json:"id" db:"id"
+	Tag string
+	//Tags 按顺序排列的各个tag
+	Tags []StructTag
+}
+
+//StructTag 单个tag
+type StructTag struct {
+	Key   string
+	Value string
+}
+
+//TagValue 获取指定tag的值,不存在时返回空字符串
+func (f StructField) TagValue(key string) string {
+	for _, tag := range f.Tags {
+		if tag.Key == key {
+			return tag.Value
+		}
+	}
+	return ""
+}
+
+//templateFuncs 模板中可用的函数
+func (g *Generator) templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"goName":    g.GoName,
+		"lower":     strings.ToLower,
+		"upper":     strings.ToUpper,
+		"join":      strings.Join,
+		"replace":   strings.Replace,
+		"hasPrefix": strings.HasPrefix,
+		"hasSuffix": strings.HasSuffix,
+		"contains":  strings.Contains,
+		"trimPrefix": func(prefix, s string) string {
+			return strings.TrimPrefix(s, prefix)
+		},
+	}
+}
+
+//loadTemplate 读取模板,未指定模板文件时使用默认模板
+func (g *Generator) loadTemplate() (*template.Template, error) {
+	text := DefaultTemplate
+	name := "default"
+	if g.opts.TemplateFile != "" {
+		content, err := ioutil.ReadFile(g.opts.TemplateFile)
+		if err != nil {
+			return nil, fmt.Errorf("读取模板文件失败:%v", err)
+		}
+		text = string(content)
+		name = g.opts.TemplateFile
+	}
+	tpl, err := template.New(name).Funcs(g.templateFuncs()).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("解析模板失败:%v", err)
+	}
+	return tpl, nil
+}
+
+//fieldTags 生成字段的tag
+func (g *Generator) fieldTags(table Table, field Field) []StructTag {
+	tags := make([]StructTag, 0)
+	if g.opts.TagJSON {
+		tags = append(tags, StructTag{Key: "json", Value: field.Name})
+	}
+	if g.opts.TagSQLX {
+		tags = append(tags, StructTag{Key: "db", Value: field.Name})
+	}
+	if g.opts.TagGORM {
+		gormTags := []string{"column:" + field.Name}
+		//SQLite中可以不声明字段类型
+		if g.opts.TagGORMType && field.OriginType != "" {
+			if strings.Contains(field.OriginType, ")") {
+				gormTags = append(gormTags, "type:"+field.OriginType[:strings.Index(field.OriginType, ")")+1])
+			} else {
+				gormTags = append(gormTags, "type:"+field.OriginType)
+			}
+		}
+		if !field.EnableNull {
+			gormTags = append(gormTags, "not null")
+		}
+		if field.IsPrimaryKey {
+			gormTags = append(gormTags, "primary_key")
+		}
+		if field.IsAutoIncrement {
+			gormTags = append(gormTags, "AUTO_INCREMENT")
+		}
+		gormTags = append(gormTags, gormIndexTags(table.Indexes, field.Name)...)
+		tags = append(tags, StructTag{Key: "gorm", Value: strings.Join(gormTags, ";")})
+	}
+	if g.opts.TagXORM {
+		xormTags := []string{"'" + field.Name + "'"}
+		if g.opts.TagXORMType && field.OriginType != "" {
+			if strings.Contains(field.OriginType, ")") {
+				xormTags = append(xormTags, field.OriginType[:strings.Index(field.OriginType, ")")+1])
+			} else {
+				xormTags = append(xormTags, field.OriginType)
+			}
+		}
+		xormTags = append(xormTags, xormIndexTags(table.Indexes, field.Name)...)
+		tags = append(tags, StructTag{Key: "xorm", Value: strings.Join(xormTags, " ")})
+	}
+	if g.opts.TagValidate {
+		if rules := g.validateRules(field); len(rules) > 0 {
+			tags = append(tags, StructTag{Key: "validate", Value: strings.Join(rules, ",")})
+		}
+	}
+	return tags
+}
+
+//StructData 生成渲染模板所需的数据
+func (g *Generator) StructData(table Table) StructData {
+	data := StructData{
+		PackageName: g.opts.PackageName,
+		GoName:      g.StructName(table.OriginName),
+		TableName:   g.opts.TablePrefix + table.Name,
+		Comment:     table.Name,
+		Fields:      make([]StructField, 0, len(table.Fields)),
+		Indexes:     table.Indexes,
+		Table:       table,
+		Schema:      table.Schema,
+	}
+	if table.Comment != "" {
+		data.Comment = table.Comment
+	}
+	if tableOptions, ok := g.opts.TableOptions[table.OriginName]; ok && tableOptions.Comment != "" {
+		data.Comment = tableOptions.Comment
+	}
+	var hasEnum = false
+	var hasSet = false
+	fieldNames := make(map[string]bool, len(table.Fields))
+	for _, field := range table.Fields {
+		structField := StructField{
+			Field:  field,
+			GoName: g.GoName(field.Name, table.Name),
+			Tags:   g.fieldTags(table, field),
+		}
+		structField.Tag = joinTags(structField.Tags)
+		data.Fields = append(data.Fields, structField)
+		if g.opts.Enums && len(field.EnumValues) > 0 {
+			//字段类型被映射规则覆盖时不生成类型
+			typeName := g.enumTypeName(data.GoName, field, table.Name)
+			if strings.TrimPrefix(field.Type, "*") == typeName {
+				data.Enums = append(data.Enums, g.enumType(typeName, field))
+				hasEnum = true
+				hasSet = hasSet || field.IsSet
+			}
+		}
+		fieldNames[structField.GoName] = true
+	}
+	data.Associations = g.associations(table, fieldNames)
+	//方法不能与字段(包括关联字段)同名
+	memberNames := make(map[string]bool, len(fieldNames)+len(data.Associations))
+	for name := range fieldNames {
+		memberNames[name] = true
+	}
+	for _, association := range data.Associations {
+		memberNames[association.GoName] = true
+	}
+	data.IndexesMethod = len(data.Indexes) > 0 && !memberNames["Indexes"]
+	if g.opts.Columns {
+		data.ColumnsVar = data.GoName + "Columns"
+		//变量名不能与本次生成的struct和enum类型同名
+		typeNames := g.structNames()
+		for _, enum := range data.Enums {
+			typeNames[enum.GoName] = true
+		}
+		for typeNames[data.ColumnsVar] {
+			data.ColumnsVar += "_"
+		}
+		data.ColumnsTableField = "Table"
+		for fieldNames[data.ColumnsTableField] {
+			data.ColumnsTableField += "_"
+		}
+		data.ColumnsMethod = !memberNames["Columns"]
+		data.FieldByColumnMethod = !memberNames["FieldByColumn"]
+	}
+	data.Imports = g.fieldImports(table.Name, table.Fields)
+	//enum和set类型的方法中用到的包
+	var enumImports []string
+	if hasEnum {
+		enumImports = append(enumImports, `"database/sql/driver"`, `"fmt"`)
+	}
+	if hasSet {
+		enumImports = append(enumImports, `"strings"`)
+	}
+	for _, spec := range enumImports {
+		if !inStrings(spec, data.Imports) {
+			data.Imports = append(data.Imports, spec)
+		}
+	}
+	return data
+}
+
+//joinTags 将tag连接为完整的tag,如json:"id" db:"id"
+func joinTags(tags []StructTag) string {
+	items := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		items = append(items, fmt.Sprintf(`%s:"%s"`, tag.Key, tag.Value))
+	}
+	return strings.Join(items, " ")
+}
+
+//RenderTable 将表转换为格式化后的golang代码
+func (g *Generator) RenderTable(table Table) ([]byte, error) {
+	buf := bytes.NewBufferString("")
+	data := g.StructData(table)
+	skipped := make([]string, 0, 3)
+	if len(data.Indexes) > 0 && !data.IndexesMethod {
+		skipped = append(skipped, "Indexes")
+	}
+	if g.opts.Columns && !data.ColumnsMethod {
+		skipped = append(skipped, "Columns")
+	}
+	if g.opts.Columns && !data.FieldByColumnMethod {
+		skipped = append(skipped, "FieldByColumn")
+	}
+	for _, method := range skipped {
+		g.warnf("表%s: 存在名为%s的字段,已跳过%s方法", table.Name, method, method)
+	}
+	if err := g.tpl.Execute(buf, data); err != nil {
+		return nil, fmt.Errorf("渲染表%s失败:%v", table.Name, err)
+	}
+	content, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("格式化失败:%v", err)
+	}
+	return content, nil
+}
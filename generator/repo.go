@@ -0,0 +1,289 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+//DefaultRepoTemplate 默认的增删改查函数模板,基于sqlx
+const DefaultRepoTemplate = `package {{.PackageName}}
+
+import (
+{{- range .Imports}}
+	{{.}}
+{{- end}}
+)
+{{- $type := .GoName}}
+{{- if .PrimaryKeys}}
+
+//Get{{$type}}ByID 根据主键获取{{$type}}
+func Get{{$type}}ByID(ctx context.Context, db sqlx.ExtContext{{range .PrimaryKeys}}, {{.Param}} {{.Type}}{{end}}) (*{{$type}}, error) {
+	var t {{$type}}
+	if err := sqlx.GetContext(ctx, db, &t, db.Rebind({{goString .GetSQL}}){{range .PrimaryKeys}}, {{.Param}}{{end}}); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+{{- end}}
+
+//List{{$type}} 分页获取{{$type}}
+func List{{$type}}(ctx context.Context, db sqlx.ExtContext, limit, offset int) ([]{{$type}}, error) {
+	var ts []{{$type}}
+	if err := sqlx.SelectContext(ctx, db, &ts, db.Rebind({{goString .ListSQL}}), limit, offset); err != nil {
+		return nil, err
+	}
+	return ts, nil
+}
+{{- if .AutoIncrement}}
+
+//Insert{{$type}} 插入一条{{$type}},返回自增的{{.AutoIncrement.GoName}}
+func Insert{{$type}}(ctx context.Context, db sqlx.ExtContext, t *{{$type}}) (int64, error) {
+{{- if .Returning}}
+	var id int64
+	err := db.QueryRowxContext(ctx, db.Rebind({{goString .InsertSQL}}){{range .InsertFields}}, t.{{.GoName}}{{end}}).Scan(&id)
+	return id, err
+{{- else}}
+	result, err := db.ExecContext(ctx, db.Rebind({{goString .InsertSQL}}){{range .InsertFields}}, t.{{.GoName}}{{end}})
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+{{- end}}
+}
+{{- else}}
+
+//Insert{{$type}} 插入一条{{$type}}
+func Insert{{$type}}(ctx context.Context, db sqlx.ExtContext, t *{{$type}}) error {
+	_, err := db.ExecContext(ctx, db.Rebind({{goString .InsertSQL}}){{range .InsertFields}}, t.{{.GoName}}{{end}})
+	return err
+}
+{{- end}}
+{{- if .InsertFields}}
+
+//BatchInsert{{$type}} 批量插入{{$type}},每{{.BatchSize}}条执行一次以免超出数据库占位符数量的限制,需要全部成功或全部失败时请传入事务
+func BatchInsert{{$type}}(ctx context.Context, db sqlx.ExtContext, ts []{{$type}}) error {
+	for len(ts) > 0 {
+		n := len(ts)
+		if n > {{.BatchSize}} {
+			n = {{.BatchSize}}
+		}
+		values := make([]string, 0, n)
+		args := make([]interface{}, 0, n*{{len .InsertFields}})
+		for i := range ts[:n] {
+			values = append(values, {{goString .InsertValues}})
+			args = append(args{{range .InsertFields}}, ts[i].{{.GoName}}{{end}})
+		}
+		if _, err := db.ExecContext(ctx, db.Rebind({{goString .BatchInsertSQL}}+strings.Join(values, ",")), args...); err != nil {
+			return err
+		}
+		ts = ts[n:]
+	}
+	return nil
+}
+{{- end}}
+{{- if and .PrimaryKeys .UpdateFields}}
+
+//Update{{$type}} 根据主键更新{{$type}}的所有字段,返回受影响的行数
+func Update{{$type}}(ctx context.Context, db sqlx.ExtContext, t *{{$type}}) (int64, error) {
+	result, err := db.ExecContext(ctx, db.Rebind({{goString .UpdateSQL}}){{range .UpdateFields}}, t.{{.GoName}}{{end}}{{range .PrimaryKeys}}, t.{{.GoName}}{{end}})
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+{{- end}}
+{{- if .PrimaryKeys}}
+
+//Delete{{$type}} 根据主键删除{{$type}},返回受影响的行数
+func Delete{{$type}}(ctx context.Context, db sqlx.ExtContext{{range .PrimaryKeys}}, {{.Param}} {{.Type}}{{end}}) (int64, error) {
+	result, err := db.ExecContext(ctx, db.Rebind({{goString .DeleteSQL}}){{range .PrimaryKeys}}, {{.Param}}{{end}})
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+{{- end}}
+`
+
+//RepoData 渲染增删改查函数模板所需的数据
+type RepoData struct {
+	StructData
+	//PrimaryKeys 主键字段
+	PrimaryKeys []RepoField
+	//AutoIncrement 自增字段,没有时为nil
+	AutoIncrement *RepoField
+	//InsertFields 插入时使用的字段,不包括自增字段和生成列
+	InsertFields []RepoField
+	//UpdateFields 更新时使用的字段,不包括主键和生成列
+	UpdateFields []RepoField
+	//BatchSize 批量插入时每条语句插入的行数
+	BatchSize int
+	//Returning 是否用RETURNING获取自增字段的值(PostgreSQL)
+	Returning bool
+
+	GetSQL         string
+	ListSQL        string
+	InsertSQL      string
+	InsertValues   string
+	BatchInsertSQL string
+	UpdateSQL      string
+	DeleteSQL      string
+}
+
+//maxPlaceholders 各数据库单条语句的占位符数量上限,SQLite取3.32.0之前的默认值
+var maxPlaceholders = map[string]int{
+	"mysql":    65535,
+	"postgres": 65535,
+	"sqlite":   999,
+}
+
+//RepoField 增删改查函数中用到的字段
+type RepoField struct {
+	StructField
+	//Param 作为函数参数时的名称,如userID
+	Param string
+}
+
+//RepoData 生成增删改查函数所需的数据
+func (g *Generator) RepoData(table Table) RepoData {
+	data := RepoData{StructData: g.StructData(table)}
+	dbType := g.dbType()
+	quote := func(name string) string {
+		if dbType == "mysql" {
+			return quoteMySQL(name)
+		}
+		return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+	}
+	var columns, insertColumns, placeholders, sets, conditions []string
+	var pkFields []Field
+	for _, field := range data.Fields {
+		repoField := RepoField{StructField: field, Param: paramName(field.GoName)}
+		column := quote(field.Name)
+		columns = append(columns, column)
+		//生成列的值由数据库计算,只能查询
+		if field.IsGenerated {
+			continue
+		}
+		if field.IsPrimaryKey {
+			data.PrimaryKeys = append(data.PrimaryKeys, repoField)
+			conditions = append(conditions, column+" = ?")
+			pkFields = append(pkFields, field.Field)
+		} else {
+			data.UpdateFields = append(data.UpdateFields, repoField)
+			sets = append(sets, column+" = ?")
+		}
+		if field.IsAutoIncrement && data.AutoIncrement == nil {
+			autoIncrement := repoField
+			data.AutoIncrement = &autoIncrement
+			continue
+		}
+		data.InsertFields = append(data.InsertFields, repoField)
+		insertColumns = append(insertColumns, column)
+		placeholders = append(placeholders, "?")
+	}
+	tableName := quote(data.TableName)
+	selectSQL := "SELECT " + strings.Join(columns, ", ") + " FROM " + tableName
+	where := " WHERE " + strings.Join(conditions, " AND ")
+	data.GetSQL = selectSQL + where
+	data.ListSQL = selectSQL
+	if len(data.PrimaryKeys) > 0 {
+		var orderBy []string
+		for _, pk := range data.PrimaryKeys {
+			orderBy = append(orderBy, quote(pk.Name))
+		}
+		data.ListSQL += " ORDER BY " + strings.Join(orderBy, ", ")
+	}
+	data.ListSQL += " LIMIT ? OFFSET ?"
+	data.BatchSize = maxPlaceholders[dbType]
+	if len(data.InsertFields) > 0 {
+		data.BatchSize /= len(data.InsertFields)
+	}
+	data.InsertValues = "(" + strings.Join(placeholders, ", ") + ")"
+	data.BatchInsertSQL = "INSERT INTO " + tableName + " (" + strings.Join(insertColumns, ", ") + ") VALUES "
+	data.InsertSQL = data.BatchInsertSQL + data.InsertValues
+	//只有自增字段时PostgreSQL和SQLite不支持空的字段列表
+	if len(insertColumns) == 0 && dbType != "mysql" {
+		data.InsertSQL = "INSERT INTO " + tableName + " DEFAULT VALUES"
+	}
+	if data.AutoIncrement != nil && dbType == "postgres" {
+		data.Returning = true
+		data.InsertSQL += " RETURNING " + quote(data.AutoIncrement.Name)
+	}
+	data.UpdateSQL = "UPDATE " + tableName + " SET " + strings.Join(sets, ", ") + where
+	data.DeleteSQL = "DELETE FROM " + tableName + where
+
+	imports := []string{`"context"`, `"github.com/jmoiron/sqlx"`}
+	if len(data.InsertFields) > 0 {
+		imports = append(imports, `"strings"`)
+	}
+	//主键作为参数时用到的包
+	for _, spec := range g.fieldImports(table.Name, pkFields) {
+		if !inStrings(spec, imports) {
+			imports = append(imports, spec)
+		}
+	}
+	sort.Strings(imports)
+	data.Imports = imports
+	return data
+}
+
+//RenderRepo 生成表的增删改查函数
+func (g *Generator) RenderRepo(table Table) ([]byte, error) {
+	if g.repoTpl == nil {
+		funcs := g.templateFuncs()
+		funcs["goString"] = goString
+		tpl, err := template.New("repo").Funcs(funcs).Parse(DefaultRepoTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("解析模板失败:%v", err)
+		}
+		g.repoTpl = tpl
+	}
+	buf := bytes.NewBufferString("")
+	if err := g.repoTpl.Execute(buf, g.RepoData(table)); err != nil {
+		return nil, fmt.Errorf("渲染表%s的增删改查函数失败:%v", table.Name, err)
+	}
+	content, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("格式化失败:%v", err)
+	}
+	return content, nil
+}
+
+//paramName 将golang字段名转换为参数名,如ID转换为id,UserID转换为userID
+func paramName(goName string) string {
+	runes := []rune(goName)
+	upper := 0
+	for upper < len(runes) && unicode.IsUpper(runes[upper]) {
+		upper++
+	}
+	switch {
+	case upper == len(runes):
+		upper = len(runes)
+	case upper > 1:
+		//UUIDValue中的V属于下一个单词
+		upper--
+	}
+	for i := 0; i < upper; i++ {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	name := string(runes)
+	if token.Lookup(name).IsKeyword() || name == "ctx" || name == "db" || name == "t" {
+		name += "Value"
+	}
+	return name
+}
+
+//goString 将sql转换为golang的字符串字面量,不包含反引号时使用原始字符串
+func goString(s string) string {
+	if strings.ContainsAny(s, "`\r") {
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
+}
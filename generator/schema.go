@@ -0,0 +1,140 @@
+package generator
+
+import "database/sql"
+
+//Mapping 映射
+type Mapping struct {
+	FieldName string
+	FieldType string
+}
+
+//Field 字段
+type Field struct {
+	//Name 字段名
+	Name string
+	//OriginName 原始名称
+	OriginName string
+	//Type 数据类型
+	Type string
+	//Import 数据类型所在的包,由类型映射规则指定
+	Import string
+	//OriginType 数据库原始类型
+	OriginType string
+	//DataType 数据库中的数据类型,不包含长度等信息,如varchar
+	DataType string
+	//Position 字段在表中的位置,从1开始
+	Position int
+	//Length 最大长度
+	Length int
+	//DecimalDigits 小数位数
+	DecimalDigits int
+	//IsUnsigned 是否为无符号整型
+	IsUnsigned bool
+	//EnableNull 是否允许为空
+	EnableNull bool
+	//IsPrimaryKey 是否是主键
+	IsPrimaryKey bool
+	//IsAutoIncrement 是否是自增字段
+	IsAutoIncrement bool
+	//IsNullType 是否是sql.NullInt64之类的类型
+	IsNullType bool
+	//IsExtNullType 是否是nulltype.NullInt64之类的类型
+	IsExtNullType bool
+	//Default 默认值
+	Default string
+	//HasDefault 是否有默认值,生成列也视为有默认值
+	HasDefault bool
+	//IsGenerated 是否为生成列
+	IsGenerated bool
+	//Comment 注释
+	Comment string
+	//EnumValues enum或set字段的可选值
+	EnumValues []string
+	//IsSet 是否为set字段
+	IsSet bool
+}
+
+//Table 表
+type Table struct {
+	Name       string
+	OriginName string
+	Fields     []Field
+	HasTime    bool
+	HasPrefix  bool
+	Comment    string
+	//Schema 原始的表信息
+	Schema TableSchema
+	//Columns 原始的字段信息,与Fields一一对应
+	Columns []ColumnSchema
+	//ForeignKeys 该表引用其他表的外键
+	ForeignKeys []ForeignKey
+	//ReferencedBy 其他表引用该表的外键
+	ReferencedBy []ForeignKey
+	//Indexes 表的索引,包括主键
+	Indexes []Index
+}
+
+//TableField 表字段属性
+type TableField struct {
+	Field      string         `db:"Field"`
+	Type       string         `db:"Type"`
+	Collation  sql.NullString `db:"Collation"`
+	Null       sql.NullString `db:"Null"`
+	Key        sql.NullString `db:"Key"`
+	Default    sql.NullString `db:"Default"`
+	Extra      sql.NullString `db:"Extra"`
+	Privileges sql.NullString `db:"Privileges"`
+	Comment    sql.NullString `db:"Comment"`
+}
+
+//TableSchema table
+type TableSchema struct {
+	TableCatalog   string         `db:"TABLE_CATALOG"`
+	TableSchema    string         `db:"TABLE_SCHEMA"`
+	TableName      string         `db:"TABLE_NAME"`
+	TableType      string         `db:"TABLE_TYPE"`
+	Engine         string         `db:"ENGINE"`
+	Version        sql.NullInt64  `db:"VERSION"`
+	RowFormat      sql.NullString `db:"ROW_FORMAT"`
+	TableRows      sql.NullInt64  `db:"TABLE_ROWS"`
+	AvgRowLength   sql.NullInt64  `db:"AVG_ROW_LENGTH"`
+	DataLength     sql.NullInt64  `db:"DATA_LENGTH"`
+	MaxDataLength  sql.NullInt64  `db:"MAX_DATA_LENGTH"`
+	IndexLength    sql.NullInt64  `db:"INDEX_LENGTH"`
+	DataFree       sql.NullInt64  `db:"DATA_FREE"`
+	AutoIncrement  sql.NullInt64  `db:"AUTO_INCREMENT"`
+	CreateTime     sql.NullString `db:"CREATE_TIME"`
+	UpdateTime     sql.NullString `db:"UPDATE_TIME"`
+	CheckTime      sql.NullString `db:"CHECK_TIME"`
+	TableCollation sql.NullString `db:"TABLE_COLLATION"`
+	Checksum       sql.NullInt64  `db:"CHECKSUM"`
+	CreateOptions  sql.NullString `db:"CREATE_OPTIONS"`
+	TableComment   sql.NullString `db:"TABLE_COMMENT"`
+}
+
+//ColumnSchema column
+type ColumnSchema struct {
+	TableCatalog           sql.NullString `db:"TABLE_CATALOG"`
+	TableSchema            string         `db:"TABLE_SCHEMA"`
+	TableName              string         `db:"TABLE_NAME"`
+	ColumnName             string         `db:"COLUMN_NAME"`
+	OrdinalPosition        sql.NullInt64  `db:"ORDINAL_POSITION"`
+	ColumnDefault          sql.NullString `db:"COLUMN_DEFAULT"`
+	IsNullAble             string         `db:"IS_NULLABLE"`
+	DataType               string         `db:"DATA_TYPE"`
+	CharacterMaximumLength sql.NullInt64  `db:"CHARACTER_MAXIMUM_LENGTH"`
+	CharacterOctetLength   sql.NullInt64  `db:"CHARACTER_OCTET_LENGTH"`
+	NumericPrecision       sql.NullInt64  `db:"NUMERIC_PRECISION"`
+	NumericScale           sql.NullInt64  `db:"NUMERIC_SCALE"`
+	DatetimePrecision      sql.NullInt64  `db:"DATETIME_PRECISION"`
+	CharacterSetName       sql.NullString `db:"CHARACTER_SET_NAME"`
+	CollationName          sql.NullString `db:"COLLATION_NAME"`
+	ColumnType             string         `db:"COLUMN_TYPE"`
+	ColumnKey              sql.NullString `db:"COLUMN_KEY"`
+	Extra                  sql.NullString `db:"EXTRA"`
+	Privileges             sql.NullString `db:"PRIVILEGES"`
+	ColumnComment          sql.NullString `db:"COLUMN_COMMENT"`
+	GenerationExpression   string         `db:"GENERATION_EXPRESSION"`
+	//EnumValues PostgreSQL中enum类型的可选值,如'a','b'
+	EnumValues sql.NullString `db:"ENUM_VALUES"`
+}
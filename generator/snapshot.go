@@ -0,0 +1,319 @@
+package generator
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+//SchemaSnapshotVersion 当前的快照格式版本,格式不兼容时递增
+const SchemaSnapshotVersion = 1
+
+//SchemaSnapshot 表结构快照,保存为json文件后可以不连接数据库进行生成和比较
+type SchemaSnapshot struct {
+	//Version 快照格式版本
+	Version int `json:"version"`
+	//DBType 数据库类型,决定字段类型的转换规则
+	DBType string `json:"db_type"`
+	//Tables 所有表
+	Tables []SnapshotTable `json:"tables"`
+}
+
+//SnapshotTable 快照中的表,对应TableSchema
+type SnapshotTable struct {
+	Name          string           `json:"name"`
+	Schema        string           `json:"schema,omitempty"`
+	Type          string           `json:"type,omitempty"`
+	Engine        string           `json:"engine,omitempty"`
+	RowFormat     string           `json:"row_format,omitempty"`
+	Rows          *int64           `json:"rows,omitempty"`
+	AutoIncrement *int64           `json:"auto_increment,omitempty"`
+	Collation     string           `json:"collation,omitempty"`
+	Comment       string           `json:"comment,omitempty"`
+	Columns       []SnapshotColumn `json:"columns"`
+	Indexes       []Index          `json:"indexes,omitempty"`
+	ForeignKeys   []ForeignKey     `json:"foreign_keys,omitempty"`
+}
+
+//SnapshotColumn 快照中的字段,对应ColumnSchema
+type SnapshotColumn struct {
+	Name                 string  `json:"name"`
+	Position             int64   `json:"position"`
+	DataType             string  `json:"data_type"`
+	ColumnType           string  `json:"column_type"`
+	Nullable             bool    `json:"nullable"`
+	Default              *string `json:"default,omitempty"`
+	Key                  string  `json:"key,omitempty"`
+	Extra                string  `json:"extra,omitempty"`
+	Comment              string  `json:"comment,omitempty"`
+	MaxLength            *int64  `json:"max_length,omitempty"`
+	OctetLength          *int64  `json:"octet_length,omitempty"`
+	Precision            *int64  `json:"precision,omitempty"`
+	Scale                *int64  `json:"scale,omitempty"`
+	DatetimePrecision    *int64  `json:"datetime_precision,omitempty"`
+	CharacterSet         string  `json:"character_set,omitempty"`
+	Collation            string  `json:"collation,omitempty"`
+	GenerationExpression string  `json:"generation_expression,omitempty"`
+	EnumValues           string  `json:"enum_values,omitempty"`
+}
+
+//NewSchemaSnapshot 根据表结构生成快照
+func NewSchemaSnapshot(dbType string, tables []DDLTable) *SchemaSnapshot {
+	if dbType == "" {
+		dbType = "mysql"
+	}
+	snapshot := &SchemaSnapshot{
+		Version: SchemaSnapshotVersion,
+		DBType:  dbType,
+		Tables:  make([]SnapshotTable, 0, len(tables)),
+	}
+	for _, t := range tables {
+		table := SnapshotTable{
+			Name:          t.Schema.TableName,
+			Schema:        t.Schema.TableSchema,
+			Type:          t.Schema.TableType,
+			Engine:        t.Schema.Engine,
+			RowFormat:     t.Schema.RowFormat.String,
+			Rows:          int64Pointer(t.Schema.TableRows),
+			AutoIncrement: int64Pointer(t.Schema.AutoIncrement),
+			Collation:     t.Schema.TableCollation.String,
+			Comment:       t.Schema.TableComment.String,
+			Columns:       make([]SnapshotColumn, 0, len(t.Columns)),
+			Indexes:       t.Indexes,
+			ForeignKeys:   t.ForeignKeys,
+		}
+		for _, col := range t.Columns {
+			column := SnapshotColumn{
+				Name:                 col.ColumnName,
+				Position:             col.OrdinalPosition.Int64,
+				DataType:             col.DataType,
+				ColumnType:           col.ColumnType,
+				Nullable:             col.IsNullAble == "YES",
+				Key:                  col.ColumnKey.String,
+				Extra:                col.Extra.String,
+				Comment:              col.ColumnComment.String,
+				MaxLength:            int64Pointer(col.CharacterMaximumLength),
+				OctetLength:          int64Pointer(col.CharacterOctetLength),
+				Precision:            int64Pointer(col.NumericPrecision),
+				Scale:                int64Pointer(col.NumericScale),
+				DatetimePrecision:    int64Pointer(col.DatetimePrecision),
+				CharacterSet:         col.CharacterSetName.String,
+				Collation:            col.CollationName.String,
+				GenerationExpression: col.GenerationExpression,
+				EnumValues:           col.EnumValues.String,
+			}
+			if col.ColumnDefault.Valid {
+				value := col.ColumnDefault.String
+				column.Default = &value
+			}
+			table.Columns = append(table.Columns, column)
+		}
+		snapshot.Tables = append(snapshot.Tables, table)
+	}
+	return snapshot
+}
+
+//ReadSchemaSnapshot 读取json格式的表结构快照
+func ReadSchemaSnapshot(filename string) (*SchemaSnapshot, error) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("读取表结构快照失败:%v", err)
+	}
+	snapshot := &SchemaSnapshot{}
+	if err := json.Unmarshal(content, snapshot); err != nil {
+		return nil, fmt.Errorf("解析表结构快照%s失败:%v", filename, err)
+	}
+	if snapshot.Version < 1 || snapshot.Version > SchemaSnapshotVersion {
+		return nil, fmt.Errorf("不支持的表结构快照版本:%d,当前支持的版本为%d", snapshot.Version, SchemaSnapshotVersion)
+	}
+	return snapshot, nil
+}
+
+//WriteFile 将快照保存为json文件
+func (s *SchemaSnapshot) WriteFile(filename string) error {
+	content, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filename, append(content, '\n'), 0666); err != nil {
+		return fmt.Errorf("保存表结构快照失败:%v", err)
+	}
+	return nil
+}
+
+//DDLTables 将快照转换为表结构
+func (s *SchemaSnapshot) DDLTables() []DDLTable {
+	tables := make([]DDLTable, 0, len(s.Tables))
+	for _, t := range s.Tables {
+		table := DDLTable{
+			Schema: TableSchema{
+				TableSchema:    t.Schema,
+				TableName:      t.Name,
+				TableType:      t.Type,
+				Engine:         t.Engine,
+				RowFormat:      nullString(t.RowFormat),
+				TableRows:      nullInt64(t.Rows),
+				AutoIncrement:  nullInt64(t.AutoIncrement),
+				TableCollation: nullString(t.Collation),
+				TableComment:   nullString(t.Comment),
+			},
+			Columns: make([]ColumnSchema, 0, len(t.Columns)),
+		}
+		for _, index := range t.Indexes {
+			index.TableName = t.Name
+			table.Indexes = append(table.Indexes, index)
+		}
+		for _, fk := range t.ForeignKeys {
+			fk.TableName = t.Name
+			table.ForeignKeys = append(table.ForeignKeys, fk)
+		}
+		for i, c := range t.Columns {
+			col := ColumnSchema{
+				TableSchema:            t.Schema,
+				TableName:              t.Name,
+				ColumnName:             c.Name,
+				OrdinalPosition:        sql.NullInt64{Int64: c.Position, Valid: true},
+				IsNullAble:             "NO",
+				DataType:               strings.ToLower(c.DataType),
+				ColumnType:             c.ColumnType,
+				CharacterMaximumLength: nullInt64(c.MaxLength),
+				CharacterOctetLength:   nullInt64(c.OctetLength),
+				NumericPrecision:       nullInt64(c.Precision),
+				NumericScale:           nullInt64(c.Scale),
+				DatetimePrecision:      nullInt64(c.DatetimePrecision),
+				CharacterSetName:       nullString(c.CharacterSet),
+				CollationName:          nullString(c.Collation),
+				ColumnKey:              nullString(c.Key),
+				Extra:                  nullString(c.Extra),
+				ColumnComment:          nullString(c.Comment),
+				GenerationExpression:   c.GenerationExpression,
+				EnumValues:             nullString(c.EnumValues),
+			}
+			if c.Position == 0 {
+				col.OrdinalPosition.Int64 = int64(i + 1)
+			}
+			if c.Nullable {
+				col.IsNullAble = "YES"
+			}
+			if c.Default != nil {
+				col.ColumnDefault = sql.NullString{String: *c.Default, Valid: true}
+			}
+			table.Columns = append(table.Columns, col)
+		}
+		tables = append(tables, table)
+	}
+	return tables
+}
+
+func int64Pointer(n sql.NullInt64) *int64 {
+	if !n.Valid {
+		return nil
+	}
+	return &n.Int64
+}
+
+func nullInt64(n *int64) sql.NullInt64 {
+	if n == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: *n, Valid: true}
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+//snapshotProvider 从表结构快照中读取表结构,字段类型按快照中的数据库类型转换
+type snapshotProvider struct {
+	Provider
+	dbType string
+	tables []DDLTable
+}
+
+//Provider 将快照转换为表结构来源
+func (s *SchemaSnapshot) Provider() (Provider, error) {
+	p := &snapshotProvider{dbType: s.DBType, tables: s.DDLTables()}
+	switch s.DBType {
+	case "", "mysql":
+		p.Provider = &MySQLProvider{}
+	case "postgres":
+		p.Provider = &PostgresProvider{}
+	case "sqlite":
+		p.Provider = &SQLiteProvider{}
+	default:
+		return nil, fmt.Errorf("表结构快照中的数据库类型不支持:%v", s.DBType)
+	}
+	return p, nil
+}
+
+//GetTables 获取所有表,args不为空时只返回指定的表
+func (p *snapshotProvider) GetTables(ctx context.Context, args []string) ([]TableSchema, error) {
+	tables := make([]TableSchema, 0, len(p.tables))
+	for _, t := range p.tables {
+		if len(args) > 0 && !inStrings(t.Schema.TableName, args) {
+			continue
+		}
+		tables = append(tables, t.Schema)
+	}
+	return tables, nil
+}
+
+//GetColumns 获取表的所有字段
+func (p *snapshotProvider) GetColumns(ctx context.Context, tableSchema TableSchema) ([]ColumnSchema, error) {
+	for _, t := range p.tables {
+		if t.Schema.TableName == tableSchema.TableName {
+			return t.Columns, nil
+		}
+	}
+	return nil, fmt.Errorf("表%s不存在", tableSchema.TableName)
+}
+
+//GetForeignKeys 获取所有外键
+func (p *snapshotProvider) GetForeignKeys(ctx context.Context) ([]ForeignKey, error) {
+	var foreignKeys []ForeignKey
+	for _, t := range p.tables {
+		foreignKeys = append(foreignKeys, t.ForeignKeys...)
+	}
+	return foreignKeys, nil
+}
+
+//GetIndexes 获取表的所有索引
+func (p *snapshotProvider) GetIndexes(ctx context.Context, tableSchema TableSchema) ([]Index, error) {
+	for _, t := range p.tables {
+		if t.Schema.TableName == tableSchema.TableName {
+			return append([]Index(nil), t.Indexes...), nil
+		}
+	}
+	return nil, fmt.Errorf("表%s不存在", tableSchema.TableName)
+}
+
+//DumpSchema 读取完整的表结构,保存为json格式的快照
+func (g *Generator) DumpSchema(ctx context.Context, filename string) error {
+	tables, err := g.LoadSchema(ctx)
+	if err != nil {
+		return err
+	}
+	return NewSchemaSnapshot(g.dbType(), tables).WriteFile(filename)
+}
+
+//DBType 表结构来源的数据库类型,为mysql、postgres或sqlite,快照为保存时的类型
+func (g *Generator) DBType() string {
+	return g.dbType()
+}
+
+func (g *Generator) dbType() string {
+	switch p := g.provider.(type) {
+	case *snapshotProvider:
+		return p.dbType
+	case *DDLSchema, *MySQLProvider:
+		return "mysql"
+	case *PostgresProvider:
+		return "postgres"
+	case *SQLiteProvider:
+		return "sqlite"
+	}
+	return g.opts.DBType
+}
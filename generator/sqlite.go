@@ -0,0 +1,281 @@
+package generator
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+//SQLiteProvider 通过sqlite_master和PRAGMA读取SQLite数据库文件的表结构
+type SQLiteProvider struct {
+	db *sqlx.DB
+}
+
+//NewSQLiteProvider 创建SQLiteProvider
+func NewSQLiteProvider(db *sqlx.DB) *SQLiteProvider {
+	return &SQLiteProvider{db: db}
+}
+
+//sqliteColumn PRAGMA table_info的结果
+type sqliteColumn struct {
+	CID          int            `db:"cid"`
+	Name         string         `db:"name"`
+	Type         string         `db:"type"`
+	NotNull      bool           `db:"notnull"`
+	DefaultValue sql.NullString `db:"dflt_value"`
+	PK           int            `db:"pk"`
+}
+
+//GetTables 获取所有表
+func (p *SQLiteProvider) GetTables(ctx context.Context, args []string) ([]TableSchema, error) {
+	tables := make([]TableSchema, 0, 32)
+	rows, err := p.db.QueryxContext(ctx, "SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name")
+	if err != nil {
+		return tables, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return tables, err
+		}
+		if len(args) > 0 && !inStrings(name, args) {
+			continue
+		}
+		tables = append(tables, TableSchema{
+			TableSchema: "main",
+			TableName:   name,
+			TableType:   "BASE TABLE",
+		})
+	}
+	return tables, rows.Err()
+}
+
+//GetColumns 获取表的所有字段
+func (p *SQLiteProvider) GetColumns(ctx context.Context, tableSchema TableSchema) ([]ColumnSchema, error) {
+	var sqliteCols []sqliteColumn
+	if err := p.db.SelectContext(ctx, &sqliteCols, fmt.Sprintf("PRAGMA table_info(%s)", quoteSQLite(tableSchema.TableName))); err != nil {
+		return nil, err
+	}
+	indexes, err := p.GetIndexes(ctx, tableSchema)
+	if err != nil {
+		return nil, err
+	}
+	//单列唯一索引的字段标记为UNI,作为其他索引第一列的字段标记为MUL
+	uniqueColumns := make(map[string]bool)
+	indexedColumns := make(map[string]bool)
+	for _, index := range indexes {
+		if len(index.Columns) == 0 || index.IsPrimary {
+			continue
+		}
+		if index.IsUnique && len(index.Columns) == 1 {
+			uniqueColumns[index.Columns[0].Name] = true
+		} else {
+			indexedColumns[index.Columns[0].Name] = true
+		}
+	}
+	pkCount := 0
+	for _, c := range sqliteCols {
+		if c.PK > 0 {
+			pkCount++
+		}
+	}
+	cols := make([]ColumnSchema, 0, len(sqliteCols))
+	for _, c := range sqliteCols {
+		col := ColumnSchema{
+			TableSchema:     tableSchema.TableSchema,
+			TableName:       tableSchema.TableName,
+			ColumnName:      c.Name,
+			OrdinalPosition: sql.NullInt64{Int64: int64(c.CID + 1), Valid: true},
+			ColumnType:      strings.ToLower(c.Type),
+			IsNullAble:      "YES",
+		}
+		col.DataType = col.ColumnType
+		if i := strings.Index(col.DataType, "("); i >= 0 {
+			col.DataType = strings.TrimSpace(col.DataType[:i])
+			fillDDLTypeLength(&col, strings.TrimSuffix(col.ColumnType[i+1:], ")"))
+		}
+		if c.NotNull || c.PK > 0 {
+			col.IsNullAble = "NO"
+		}
+		if c.DefaultValue.Valid && !strings.EqualFold(c.DefaultValue.String, "NULL") {
+			col.ColumnDefault = sql.NullString{String: strings.Trim(c.DefaultValue.String, "'"), Valid: true}
+		}
+		switch {
+		case c.PK > 0:
+			col.ColumnKey = sql.NullString{String: "PRI", Valid: true}
+			//INTEGER PRIMARY KEY是rowid的别名,会自动增长
+			if pkCount == 1 && col.ColumnType == "integer" {
+				col.Extra = sql.NullString{String: "auto_increment", Valid: true}
+			}
+		case uniqueColumns[c.Name]:
+			col.ColumnKey = sql.NullString{String: "UNI", Valid: true}
+		case indexedColumns[c.Name]:
+			col.ColumnKey = sql.NullString{String: "MUL", Valid: true}
+		}
+		cols = append(cols, col)
+	}
+	return cols, nil
+}
+
+//GetIndexes 通过PRAGMA index_list/index_info获取表的所有索引
+//
+//INTEGER PRIMARY KEY是rowid的别名,不会出现在index_list中,因此根据table_info补充名为PRIMARY的主键
+func (p *SQLiteProvider) GetIndexes(ctx context.Context, tableSchema TableSchema) ([]Index, error) {
+	rows, err := p.db.QueryxContext(ctx, fmt.Sprintf("PRAGMA index_list(%s)", quoteSQLite(tableSchema.TableName)))
+	if err != nil {
+		return nil, err
+	}
+	var indexes []Index
+	hasPrimary := false
+	for rows.Next() {
+		//不同版本的SQLite返回的列数不同
+		m := make(map[string]interface{})
+		if err = rows.MapScan(m); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		index := Index{
+			Name:      fmt.Sprintf("%s", m["name"]),
+			TableName: tableSchema.TableName,
+			IsUnique:  fmt.Sprintf("%v", m["unique"]) == "1",
+		}
+		if origin, ok := m["origin"]; ok && fmt.Sprintf("%s", origin) == "pk" {
+			index.IsPrimary = true
+			hasPrimary = true
+		}
+		indexes = append(indexes, index)
+	}
+	rows.Close()
+	for i := range indexes {
+		infoRows, err := p.db.QueryxContext(ctx, fmt.Sprintf("PRAGMA index_info(%s)", quoteSQLite(indexes[i].Name)))
+		if err != nil {
+			return nil, err
+		}
+		for infoRows.Next() {
+			var seqno, cid int
+			var name sql.NullString
+			if err := infoRows.Scan(&seqno, &cid, &name); err != nil {
+				infoRows.Close()
+				return nil, err
+			}
+			//表达式索引没有字段名
+			if name.Valid {
+				indexes[i].Columns = append(indexes[i].Columns, IndexColumn{Name: name.String})
+			}
+		}
+		infoRows.Close()
+	}
+	if !hasPrimary {
+		var sqliteCols []sqliteColumn
+		if err := p.db.SelectContext(ctx, &sqliteCols, fmt.Sprintf("PRAGMA table_info(%s)", quoteSQLite(tableSchema.TableName))); err != nil {
+			return nil, err
+		}
+		primary := Index{Name: "PRIMARY", TableName: tableSchema.TableName, IsPrimary: true, IsUnique: true}
+		for _, c := range sqliteCols {
+			if c.PK > 0 {
+				primary.Columns = append(primary.Columns, IndexColumn{Name: c.Name})
+			}
+		}
+		if len(primary.Columns) > 0 {
+			indexes = append([]Index{primary}, indexes...)
+		}
+	}
+	return indexes, nil
+}
+
+//ParseField 解析字段
+func (p *SQLiteProvider) ParseField(col ColumnSchema, opts *Options) Field {
+	var field Field
+	if col.IsNullAble == "YES" {
+		field.EnableNull = true
+	}
+	if strings.Contains(col.ColumnKey.String, "PRI") {
+		field.IsPrimaryKey = true
+	}
+	if strings.Contains(col.Extra.String, "auto_increment") {
+		field.IsAutoIncrement = true
+	}
+	field.Name = col.ColumnName
+	field.Type, field.IsNullType, field.IsExtNullType = p.GoType(col.DataType, field.EnableNull, opts)
+	field.Default = col.ColumnDefault.String
+	field.OriginType = col.ColumnType
+	return field
+}
+
+//GoType 将SQLite声明的类型转换为golang类型
+//
+//先识别常见的声明类型(如DATETIME、BOOLEAN),其余按SQLite的类型亲和性规则转换
+func (p *SQLiteProvider) GoType(dbType string, isNullAble bool, opts *Options) (goType string, isNullType bool, isExtNullType bool) {
+	//没有声明类型时为BLOB亲和性
+	if dbType == "" {
+		dbType = "blob"
+	}
+	return opts.matchTypeRules(sqliteTypeRules(), ColumnSchema{DataType: dbType, ColumnType: dbType}, isNullAble)
+}
+
+func quoteSQLite(name string) string {
+	return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+}
+
+//sqliteForeignKey PRAGMA foreign_key_list的结果
+type sqliteForeignKey struct {
+	ID       int            `db:"id"`
+	Seq      int            `db:"seq"`
+	Table    string         `db:"table"`
+	From     string         `db:"from"`
+	To       sql.NullString `db:"to"`
+	OnUpdate string         `db:"on_update"`
+	OnDelete string         `db:"on_delete"`
+	Match    string         `db:"match"`
+}
+
+//GetForeignKeys 获取所有外键
+func (p *SQLiteProvider) GetForeignKeys(ctx context.Context) ([]ForeignKey, error) {
+	tables, err := p.GetTables(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	var foreignKeys []ForeignKey
+	for _, table := range tables {
+		var rows []sqliteForeignKey
+		if err := p.db.SelectContext(ctx, &rows, fmt.Sprintf("PRAGMA foreign_key_list(%s)", quoteSQLite(table.TableName))); err != nil {
+			return nil, err
+		}
+		start := len(foreignKeys)
+		for _, row := range rows {
+			if row.Seq == 0 {
+				foreignKeys = append(foreignKeys, ForeignKey{
+					Name:         fmt.Sprintf("%s_fk_%d", table.TableName, row.ID),
+					TableName:    table.TableName,
+					RefTableName: row.Table,
+					OnUpdate:     row.OnUpdate,
+					OnDelete:     row.OnDelete,
+				})
+			}
+			fk := &foreignKeys[len(foreignKeys)-1]
+			fk.Columns = append(fk.Columns, row.From)
+			fk.RefColumns = append(fk.RefColumns, row.To.String)
+		}
+		//省略引用字段时引用的是主键
+		for i := start; i < len(foreignKeys); i++ {
+			if foreignKeys[i].RefColumns[0] != "" {
+				continue
+			}
+			var refCols []sqliteColumn
+			if err := p.db.SelectContext(ctx, &refCols, fmt.Sprintf("PRAGMA table_info(%s)", quoteSQLite(foreignKeys[i].RefTableName))); err != nil {
+				return nil, err
+			}
+			foreignKeys[i].RefColumns = foreignKeys[i].RefColumns[:0]
+			for _, col := range refCols {
+				if col.PK > 0 {
+					foreignKeys[i].RefColumns = append(foreignKeys[i].RefColumns, col.Name)
+				}
+			}
+		}
+	}
+	return foreignKeys, nil
+}
@@ -0,0 +1,1030 @@
+package generator
+
+import (
+	"database/sql"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+//structParser 从go源文件中解析struct
+type structParser struct {
+	//structs 源文件中定义的struct
+	structs map[string]*ast.StructType
+	//structNames struct的定义顺序
+	structNames []string
+	//docs struct的注释
+	docs map[string]string
+	//tableNames TableName方法返回的表名
+	tableNames map[string]string
+	//namedTypes 基于基本类型定义的类型,如type UserStatus string
+	namedTypes map[string]string
+	//enums 字符串类型的常量,用于还原enum字段
+	enums map[string][]string
+	//stringLists []string类型的变量,用于还原set字段
+	stringLists map[string][]string
+	//embedded 被匿名嵌入其他struct的struct
+	embedded map[string]bool
+	//columns 每个struct解析出的字段,用于还原外键
+	columns map[string][]structColumn
+	//associations 每个struct中带有gorm的foreignKey的关联字段
+	associations map[string][]structAssociation
+	warnings     []string
+}
+
+//structColumn 从struct字段中解析出的字段
+type structColumn struct {
+	Name string
+	//GoName golang字段名,用于还原外键
+	GoName        string
+	Type          string
+	IsNullAble    bool
+	IsPrimaryKey  bool
+	AutoIncrement bool
+	Default       string
+	HasDefault    bool
+	Comment       string
+	Unique        bool
+	Indexes       []structIndex
+}
+
+//structIndex 字段所在的索引
+type structIndex struct {
+	Name     string
+	Unique   bool
+	Class    string
+	Length   int
+	Priority int
+}
+
+//structAssociation gorm的关联字段,如User *User `gorm:"foreignKey:UserID;references:ID"`
+type structAssociation struct {
+	GoName string
+	//Type 关联的struct
+	Type string
+	//IsSlice 是否为has-many关联
+	IsSlice bool
+	//ForeignKey 外键字段的golang名称
+	ForeignKey []string
+	//References 被引用字段的golang名称,为空时为主键
+	References []string
+	OnUpdate   string
+	OnDelete   string
+}
+
+//structTagSetting gorm tag中的一项,如type:varchar(20)
+type structTagSetting struct {
+	Key   string
+	Value string
+}
+
+//ParseStructFiles 解析go源文件中带有gorm、xorm、sqlx或json tag的struct,还原出表结构。
+//paths可以是文件或目录,目录中的_test.go文件会被忽略。无法识别类型的字段会被跳过并返回警告
+func ParseStructFiles(paths []string) (*DDLSchema, []string, error) {
+	p := &structParser{
+		structs:      make(map[string]*ast.StructType),
+		docs:         make(map[string]string),
+		tableNames:   make(map[string]string),
+		namedTypes:   make(map[string]string),
+		enums:        make(map[string][]string),
+		stringLists:  make(map[string][]string),
+		embedded:     make(map[string]bool),
+		columns:      make(map[string][]structColumn),
+		associations: make(map[string][]structAssociation),
+	}
+	files, err := goSourceFiles(paths)
+	if err != nil {
+		return nil, nil, err
+	}
+	fset := token.NewFileSet()
+	for _, filename := range files {
+		f, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
+		if err != nil {
+			return nil, nil, fmt.Errorf("解析文件%s失败:%v", filename, err)
+		}
+		p.collect(f)
+	}
+	schema := &DDLSchema{}
+	for _, name := range p.structNames {
+		if !p.isModel(name) {
+			continue
+		}
+		table, err := p.parseTable(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(table.Columns) == 0 {
+			p.warnf("struct%s没有可以转换的字段,已跳过", name)
+			continue
+		}
+		schema.Tables = append(schema.Tables, *table)
+	}
+	if len(schema.Tables) == 0 {
+		return nil, nil, fmt.Errorf("没有找到可以转换的struct")
+	}
+	p.applyForeignKeys(schema)
+	sortTablesByForeignKey(schema.Tables)
+	return schema, p.warnings, nil
+}
+
+//goSourceFiles 获取所有go源文件,目录只读取第一层
+func goSourceFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("错误的输入路径:%v", path)
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		infos, err := ioutil.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("读取目录%s失败:%v", path, err)
+		}
+		for _, info := range infos {
+			name := info.Name()
+			if info.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+				continue
+			}
+			files = append(files, filepath.Join(path, name))
+		}
+	}
+	return files, nil
+}
+
+func (p *structParser) warnf(format string, args ...interface{}) {
+	p.warnings = append(p.warnings, fmt.Sprintf(format, args...))
+}
+
+//collect 收集文件中的struct、TableName方法以及enum和set类型的值
+func (p *structParser) collect(f *ast.File) {
+	for _, decl := range f.Decls {
+		switch decl := decl.(type) {
+		case *ast.GenDecl:
+			switch decl.Tok {
+			case token.TYPE:
+				for _, spec := range decl.Specs {
+					p.collectType(decl, spec.(*ast.TypeSpec))
+				}
+			case token.CONST:
+				var lastType string
+				for _, spec := range decl.Specs {
+					vs := spec.(*ast.ValueSpec)
+					if vs.Type != nil {
+						lastType = types.ExprString(vs.Type)
+					} else if len(vs.Values) > 0 {
+						lastType = ""
+					}
+					for _, value := range vs.Values {
+						if s, ok := stringLiteral(value); ok && lastType != "" {
+							p.enums[lastType] = append(p.enums[lastType], s)
+						}
+					}
+				}
+			case token.VAR:
+				for _, spec := range decl.Specs {
+					vs := spec.(*ast.ValueSpec)
+					for i, value := range vs.Values {
+						lit, ok := value.(*ast.CompositeLit)
+						if !ok || i >= len(vs.Names) || types.ExprString(lit.Type) != "[]string" {
+							continue
+						}
+						var values []string
+						for _, elt := range lit.Elts {
+							if s, ok := stringLiteral(elt); ok {
+								values = append(values, s)
+							}
+						}
+						p.stringLists[vs.Names[i].Name] = values
+					}
+				}
+			}
+		case *ast.FuncDecl:
+			if decl.Name.Name != "TableName" || decl.Recv == nil || len(decl.Recv.List) == 0 || decl.Body == nil {
+				continue
+			}
+			recv := strings.TrimPrefix(types.ExprString(decl.Recv.List[0].Type), "*")
+			for _, stmt := range decl.Body.List {
+				ret, ok := stmt.(*ast.ReturnStmt)
+				if !ok || len(ret.Results) != 1 {
+					continue
+				}
+				if s, ok := stringLiteral(ret.Results[0]); ok {
+					p.tableNames[recv] = s
+				}
+			}
+		}
+	}
+}
+
+func (p *structParser) collectType(decl *ast.GenDecl, spec *ast.TypeSpec) {
+	name := spec.Name.Name
+	switch t := spec.Type.(type) {
+	case *ast.StructType:
+		if _, ok := p.structs[name]; !ok {
+			p.structNames = append(p.structNames, name)
+		}
+		p.structs[name] = t
+		doc := spec.Doc
+		if doc == nil && len(decl.Specs) == 1 {
+			doc = decl.Doc
+		}
+		p.docs[name] = commentText(doc, name)
+		for _, field := range t.Fields.List {
+			if len(field.Names) == 0 {
+				p.embedded[strings.TrimPrefix(types.ExprString(field.Type), "*")] = true
+			}
+		}
+	case *ast.Ident:
+		p.namedTypes[name] = t.Name
+	}
+}
+
+//stringLiteral 获取字符串字面量的值
+func stringLiteral(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	s, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", false
+	}
+	return s, true
+}
+
+//commentText 获取注释内容,去掉开头的名称,如//User 用户表中的User
+func commentText(group *ast.CommentGroup, name string) string {
+	if group == nil {
+		return ""
+	}
+	text := strings.Join(strings.Fields(group.Text()), " ")
+	if text == name {
+		return ""
+	}
+	return strings.TrimPrefix(text, name+" ")
+}
+
+//isModel 判断struct是否对应一张表:有TableName方法,或者没有被嵌入其他struct且字段带有tag
+func (p *structParser) isModel(name string) bool {
+	if _, ok := p.tableNames[name]; ok {
+		return true
+	}
+	if p.embedded[name] || !ast.IsExported(name) {
+		return false
+	}
+	for _, field := range p.structs[name].Fields.List {
+		tag := fieldTag(field)
+		for _, key := range []string{"gorm", "xorm", "db", "json"} {
+			if _, ok := tag.Lookup(key); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func fieldTag(field *ast.Field) reflect.StructTag {
+	if field.Tag == nil {
+		return ""
+	}
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return ""
+	}
+	return reflect.StructTag(tag)
+}
+
+//parseTable 将struct转换为表结构
+func (p *structParser) parseTable(name string) (*DDLTable, error) {
+	table := &DDLTable{}
+	table.Schema.TableName = p.tableNames[name]
+	if table.Schema.TableName == "" {
+		table.Schema.TableName = toSnakeName(name)
+	}
+	table.Schema.TableType = "BASE TABLE"
+	if comment := p.docs[name]; comment != "" && comment != table.Schema.TableName {
+		table.Schema.TableComment = sql.NullString{String: comment, Valid: true}
+	}
+	var columns []structColumn
+	p.parseFields(name, p.structs[name], "", &columns, map[string]bool{name: true})
+	p.columns[name] = columns
+	//没有指定主键时按gorm的约定使用ID字段
+	hasPrimaryKey := false
+	for _, col := range columns {
+		if col.IsPrimaryKey {
+			hasPrimaryKey = true
+			break
+		}
+	}
+	if !hasPrimaryKey {
+		for i := range columns {
+			if columns[i].Name == "id" {
+				columns[i].IsPrimaryKey = true
+				columns[i].IsNullAble = false
+				columns[i].AutoIncrement = isIntegerSQLType(columns[i].Type)
+				break
+			}
+		}
+	}
+
+	var keys []ddlKey
+	primary := ddlKey{Kind: "PRI"}
+	indexes := make(map[string]*ddlKey)
+	var indexNames []string
+	indexPriorities := make(map[string][]int)
+	for _, col := range columns {
+		column, err := parseColumnDefinition(col.definition())
+		if err != nil {
+			return nil, fmt.Errorf("struct%s的字段%s: %v", name, col.Name, err)
+		}
+		column.TableName = table.Schema.TableName
+		column.OrdinalPosition = sql.NullInt64{Int64: int64(len(table.Columns) + 1), Valid: true}
+		table.Columns = append(table.Columns, column)
+		if col.IsPrimaryKey {
+			primary.Columns = append(primary.Columns, col.Name)
+			primary.SubParts = append(primary.SubParts, 0)
+		}
+		if col.Unique {
+			keys = append(keys, ddlKey{Kind: "UNI", Columns: []string{col.Name}, SubParts: []int{0}})
+		}
+		for _, index := range col.Indexes {
+			key, ok := indexes[index.Name]
+			if !ok {
+				key = &ddlKey{Kind: "MUL", Name: index.Name}
+				indexes[index.Name] = key
+				indexNames = append(indexNames, index.Name)
+			}
+			//嵌入的struct中可能重复声明同一个索引
+			if inStrings(col.Name, key.Columns) {
+				continue
+			}
+			if index.Unique {
+				key.Kind = "UNI"
+			}
+			if index.Class != "" {
+				key.Type = index.Class
+			}
+			//按priority插入,priority相同时保持字段顺序
+			priorities := indexPriorities[index.Name]
+			i := sort.Search(len(priorities), func(i int) bool { return priorities[i] > index.Priority })
+			priorities = append(priorities[:i], append([]int{index.Priority}, priorities[i:]...)...)
+			indexPriorities[index.Name] = priorities
+			key.Columns = append(key.Columns[:i], append([]string{col.Name}, key.Columns[i:]...)...)
+			key.SubParts = append(key.SubParts[:i], append([]int{index.Length}, key.SubParts[i:]...)...)
+		}
+	}
+	if len(primary.Columns) > 0 {
+		keys = append([]ddlKey{primary}, keys...)
+	}
+	for _, name := range indexNames {
+		keys = append(keys, *indexes[name])
+	}
+	applyDDLKeys(table, keys)
+	return table, nil
+}
+
+//parseFields 解析struct的字段,匿名嵌入和带有embedded的struct字段会被展开
+func (p *structParser) parseFields(structName string, st *ast.StructType, prefix string, columns *[]structColumn, visited map[string]bool) {
+	tableName := p.tableNames[structName]
+	if tableName == "" {
+		tableName = toSnakeName(structName)
+	}
+	for _, field := range st.Fields.List {
+		tag := fieldTag(field)
+		gormTags := parseGORMTag(tag.Get("gorm"))
+		xormTags := splitXORMTag(tag.Get("xorm"))
+		goType := types.ExprString(field.Type)
+		//关联字段可能带有xorm:"-",需要在跳过之前记录
+		if s := gormSetting(gormTags, "FOREIGNKEY"); s != nil && len(field.Names) > 0 && gormSetting(gormTags, "-") == nil {
+			association := structAssociation{
+				GoName:     field.Names[0].Name,
+				Type:       strings.TrimLeft(goType, "*[]"),
+				IsSlice:    strings.HasPrefix(goType, "[]"),
+				ForeignKey: splitGORMList(s.Value),
+			}
+			if s := gormSetting(gormTags, "REFERENCES"); s != nil {
+				association.References = splitGORMList(s.Value)
+			}
+			if s := gormSetting(gormTags, "CONSTRAINT"); s != nil {
+				association.OnUpdate, association.OnDelete = gormConstraint(s.Value)
+			}
+			p.associations[structName] = append(p.associations[structName], association)
+		}
+		if gormSetting(gormTags, "-") != nil || (len(xormTags) == 1 && xormTags[0] == "-") {
+			continue
+		}
+		baseType := strings.TrimPrefix(goType, "*")
+		embedded := len(field.Names) == 0 || gormSetting(gormTags, "EMBEDDED") != nil || inStrings("extends", xormTags)
+		if embedded {
+			switch {
+			case baseType == "gorm.Model":
+				*columns = append(*columns, gormModelColumns(tableName)...)
+			case p.structs[baseType] != nil && !visited[baseType]:
+				embeddedPrefix := prefix
+				if s := gormSetting(gormTags, "EMBEDDEDPREFIX"); s != nil {
+					embeddedPrefix += s.Value
+				}
+				visited[baseType] = true
+				p.parseFields(structName, p.structs[baseType], embeddedPrefix, columns, visited)
+				delete(visited, baseType)
+			default:
+				p.warnf("struct%s: 无法展开嵌入的类型%s,已跳过", structName, goType)
+			}
+			continue
+		}
+		//关联字段不对应表中的字段
+		if gormSetting(gormTags, "FOREIGNKEY") != nil || gormSetting(gormTags, "MANY2MANY") != nil ||
+			gormSetting(gormTags, "POLYMORPHIC") != nil || p.structs[strings.TrimLeft(goType, "*[]")] != nil {
+			continue
+		}
+		for _, ident := range field.Names {
+			if !ast.IsExported(ident.Name) {
+				continue
+			}
+			col, ok := p.parseColumn(structName, tableName, ident.Name, goType, tag, gormTags, xormTags)
+			if !ok {
+				continue
+			}
+			if col.Comment == "" {
+				col.Comment = commentText(field.Doc, ident.Name)
+			}
+			if col.Comment == "" {
+				col.Comment = commentText(field.Comment, ident.Name)
+			}
+			col.Name = prefix + col.Name
+			col.GoName = ident.Name
+			*columns = append(*columns, col)
+		}
+	}
+}
+
+//parseColumn 根据字段的类型和tag还原表中的字段
+func (p *structParser) parseColumn(structName, tableName, goName, goType string, tag reflect.StructTag, gormTags []structTagSetting, xormTags []string) (structColumn, bool) {
+	col := structColumn{Name: columnNameFromTag(tag, gormTags, xormTags)}
+	if col.Name == "-" {
+		return col, false
+	}
+	if col.Name == "" {
+		col.Name = toSnakeName(goName)
+	}
+	sqlType, nullable, ok := p.sqlType(goType)
+	if s := gormSetting(gormTags, "TYPE"); s != nil && s.Value != "" {
+		sqlType, ok = s.Value, true
+	} else if t := xormType(xormTags); t != "" {
+		sqlType, ok = t, true
+	} else if s := gormSetting(gormTags, "SIZE"); s != nil && strings.HasPrefix(sqlType, "varchar(") {
+		sqlType = "varchar(" + s.Value + ")"
+	}
+	if !ok {
+		p.warnf("struct%s的字段%s: 无法识别的类型%s,已跳过,可以在gorm或xorm的tag中指定type", structName, goName, goType)
+		return col, false
+	}
+	//gorm和xorm的tag中的类型不包含unsigned
+	if strings.HasPrefix(strings.TrimLeft(goType, "*"), "uint") && isIntegerSQLType(sqlType) && !strings.Contains(strings.ToLower(sqlType), "unsigned") {
+		sqlType += " unsigned"
+	}
+	col.Type = sqlType
+	col.IsNullAble = nullable
+
+	if len(gormTags) > 0 {
+		col.IsNullAble = gormSetting(gormTags, "NOTNULL") == nil
+		col.IsPrimaryKey = gormSetting(gormTags, "PRIMARYKEY") != nil
+		if s := gormSetting(gormTags, "AUTOINCREMENT"); s != nil && !strings.EqualFold(s.Value, "false") {
+			col.AutoIncrement = true
+		}
+		if s := gormSetting(gormTags, "DEFAULT"); s != nil {
+			col.Default, col.HasDefault = s.Value, true
+		}
+		if s := gormSetting(gormTags, "COMMENT"); s != nil {
+			col.Comment = s.Value
+		}
+		col.Unique = gormSetting(gormTags, "UNIQUE") != nil
+		for _, s := range gormTags {
+			if s.Key == "INDEX" || s.Key == "UNIQUEINDEX" {
+				col.Indexes = append(col.Indexes, gormIndex(s, tableName, col.Name))
+			}
+		}
+	}
+	for i := 0; i < len(xormTags); i++ {
+		t := xormTags[i]
+		lower := strings.ToLower(t)
+		switch {
+		case lower == "pk":
+			col.IsPrimaryKey = true
+		case lower == "autoincr":
+			col.AutoIncrement = true
+		case lower == "notnull":
+			col.IsNullAble = false
+		case lower == "null":
+			col.IsNullAble = true
+		case lower == "default" && i+1 < len(xormTags):
+			i++
+			col.Default, col.HasDefault = xormTags[i], true
+		case strings.HasPrefix(lower, "default(") && strings.HasSuffix(t, ")"):
+			col.Default, col.HasDefault = t[len("default("):len(t)-1], true
+		case strings.HasPrefix(lower, "comment(") && strings.HasSuffix(t, ")"):
+			col.Comment = strings.Trim(t[len("comment("):len(t)-1], "'")
+		case lower == "unique" || lower == "index":
+			prefix := "IDX_"
+			if lower == "unique" {
+				prefix = "UQE_"
+			}
+			col.Indexes = addStructIndex(col.Indexes, structIndex{Name: prefix + tableName + "_" + col.Name, Unique: lower == "unique", Priority: 10})
+		case strings.HasPrefix(lower, "unique(") || strings.HasPrefix(lower, "index("):
+			open := strings.Index(t, "(")
+			col.Indexes = addStructIndex(col.Indexes, structIndex{
+				Name:     strings.TrimSuffix(t[open+1:], ")"),
+				Unique:   strings.HasPrefix(lower, "unique("),
+				Priority: 10,
+			})
+		}
+	}
+	if col.IsPrimaryKey {
+		col.IsNullAble = false
+	}
+	return col, true
+}
+
+//addStructIndex 添加xorm的tag中的索引,gorm的tag中已经有同名索引时以gorm的设置为准
+func addStructIndex(indexes []structIndex, index structIndex) []structIndex {
+	for _, existing := range indexes {
+		if strings.EqualFold(existing.Name, index.Name) {
+			return indexes
+		}
+	}
+	return append(indexes, index)
+}
+
+//columnNameFromTag 从tag中获取字段名,优先级为gorm、xorm、db、json
+func columnNameFromTag(tag reflect.StructTag, gormTags []structTagSetting, xormTags []string) string {
+	if s := gormSetting(gormTags, "COLUMN"); s != nil && s.Value != "" {
+		return s.Value
+	}
+	for _, t := range xormTags {
+		if len(t) > 1 && strings.HasPrefix(t, "'") && strings.HasSuffix(t, "'") {
+			return strings.Trim(t, "'")
+		}
+	}
+	for _, key := range []string{"db", "json"} {
+		if value, ok := tag.Lookup(key); ok {
+			if name := strings.Split(value, ",")[0]; name != "" {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+//parseGORMTag 解析gorm的tag,键统一转换为大写并去掉下划线和空格,如primary_key转换为PRIMARYKEY
+func parseGORMTag(tag string) []structTagSetting {
+	var settings []structTagSetting
+	for _, item := range strings.Split(tag, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		kv := strings.SplitN(item, ":", 2)
+		key := strings.ToUpper(kv[0])
+		key = strings.Replace(strings.Replace(key, "_", "", -1), " ", "", -1)
+		setting := structTagSetting{Key: key}
+		if len(kv) > 1 {
+			setting.Value = strings.TrimSpace(kv[1])
+		}
+		settings = append(settings, setting)
+	}
+	return settings
+}
+
+func gormSetting(settings []structTagSetting, key string) *structTagSetting {
+	for i := range settings {
+		if settings[i].Key == key {
+			return &settings[i]
+		}
+	}
+	return nil
+}
+
+//splitGORMList 拆分gorm的tag中用逗号分隔的字段名,如foreignKey:UserID,TenantID
+func splitGORMList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+//gormConstraint 解析gorm的外键约束,如constraint:OnUpdate:CASCADE,OnDelete:SET NULL
+func gormConstraint(value string) (onUpdate, onDelete string) {
+	for _, item := range strings.Split(value, ",") {
+		kv := strings.SplitN(item, ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		action := strings.ToUpper(strings.TrimSpace(kv[1]))
+		switch strings.ToUpper(strings.TrimSpace(kv[0])) {
+		case "ONUPDATE":
+			onUpdate = action
+		case "ONDELETE":
+			onDelete = action
+		}
+	}
+	return onUpdate, onDelete
+}
+
+//applyForeignKeys 根据关联字段的foreignKey和references还原外键。
+//外键字段在关联字段所在的struct中时为belongs-to,否则外键在关联的struct中(has-one、has-many),两边都声明时只保留一个
+func (p *structParser) applyForeignKeys(schema *DDLSchema) {
+	for _, structName := range p.structNames {
+		for _, association := range p.associations[structName] {
+			if _, ok := p.columns[association.Type]; !ok {
+				p.warnf("struct%s的字段%s: 关联的struct%s没有对应的表,已忽略外键", structName, association.GoName, association.Type)
+				continue
+			}
+			child, parent := association.Type, structName
+			if _, ok := structColumnNames(p.columns[structName], association.ForeignKey); ok && !association.IsSlice {
+				child, parent = structName, association.Type
+			}
+			columns, ok := structColumnNames(p.columns[child], association.ForeignKey)
+			if !ok {
+				p.warnf("struct%s的字段%s: 找不到外键字段%s,已忽略外键", structName, association.GoName, strings.Join(association.ForeignKey, ","))
+				continue
+			}
+			var refColumns []string
+			if len(association.References) > 0 {
+				refColumns, ok = structColumnNames(p.columns[parent], association.References)
+			} else {
+				for _, col := range p.columns[parent] {
+					if col.IsPrimaryKey {
+						refColumns = append(refColumns, col.Name)
+					}
+				}
+			}
+			if !ok || len(refColumns) != len(columns) {
+				p.warnf("struct%s的字段%s: 找不到外键引用的字段,已忽略外键", structName, association.GoName)
+				continue
+			}
+			key := ddlKey{
+				Kind:       "FOREIGN",
+				Name:       "fk_" + p.tableName(structName) + "_" + toSnakeName(association.GoName),
+				Columns:    columns,
+				RefTable:   p.tableName(parent),
+				RefColumns: refColumns,
+				OnUpdate:   association.OnUpdate,
+				OnDelete:   association.OnDelete,
+			}
+			for i := range schema.Tables {
+				table := &schema.Tables[i]
+				if table.Schema.TableName == p.tableName(child) && !hasForeignKey(table.ForeignKeys, key) {
+					applyDDLKeys(table, []ddlKey{key})
+				}
+			}
+		}
+	}
+}
+
+//sortTablesByForeignKey 将被外键引用的表排在引用它的表之前,使建表语句可以按顺序执行。
+//没有依赖关系的表保持原来的顺序,存在循环引用时先输出剩下的表中名称最小的
+func sortTablesByForeignKey(tables []DDLTable) {
+	pending := make(map[string]bool, len(tables))
+	for _, table := range tables {
+		pending[table.Schema.TableName] = true
+	}
+	ready := func(table DDLTable) bool {
+		for _, fk := range table.ForeignKeys {
+			if fk.RefTableName != table.Schema.TableName && pending[fk.RefTableName] {
+				return false
+			}
+		}
+		return true
+	}
+	sorted := make([]DDLTable, 0, len(tables))
+	remaining := append([]DDLTable(nil), tables...)
+	for len(remaining) > 0 {
+		next := -1
+		for i, table := range remaining {
+			if ready(table) {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			next = 0
+			for i, table := range remaining {
+				if table.Schema.TableName < remaining[next].Schema.TableName {
+					next = i
+				}
+			}
+		}
+		sorted = append(sorted, remaining[next])
+		delete(pending, remaining[next].Schema.TableName)
+		remaining = append(remaining[:next], remaining[next+1:]...)
+	}
+	copy(tables, sorted)
+}
+
+//structColumnNames 将golang字段名转换为表中的字段名
+func structColumnNames(columns []structColumn, goNames []string) ([]string, bool) {
+	if len(goNames) == 0 {
+		return nil, false
+	}
+	names := make([]string, 0, len(goNames))
+	for _, goName := range goNames {
+		found := false
+		for _, col := range columns {
+			if col.GoName == goName {
+				names = append(names, col.Name)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	return names, true
+}
+
+//hasForeignKey 是否已经有引用相同字段的外键
+func hasForeignKey(foreignKeys []ForeignKey, key ddlKey) bool {
+	for _, fk := range foreignKeys {
+		if fk.RefTableName == key.RefTable && strings.Join(fk.Columns, ",") == strings.Join(key.Columns, ",") &&
+			strings.Join(fk.RefColumns, ",") == strings.Join(key.RefColumns, ",") {
+			return true
+		}
+	}
+	return false
+}
+
+//tableName struct对应的表名
+func (p *structParser) tableName(structName string) string {
+	if tableName := p.tableNames[structName]; tableName != "" {
+		return tableName
+	}
+	return toSnakeName(structName)
+}
+
+//gormIndex 解析gorm的索引设置,如index:idx_name,priority:2,length:10,class:FULLTEXT
+func gormIndex(setting structTagSetting, tableName, columnName string) structIndex {
+	index := structIndex{Unique: setting.Key == "UNIQUEINDEX", Priority: 10}
+	for i, item := range strings.Split(setting.Value, ",") {
+		kv := strings.SplitN(item, ":", 2)
+		if len(kv) == 1 {
+			if i == 0 {
+				index.Name = strings.TrimSpace(item)
+			} else if strings.EqualFold(item, "unique") {
+				index.Unique = true
+			}
+			continue
+		}
+		value := strings.TrimSpace(kv[1])
+		switch strings.ToUpper(strings.TrimSpace(kv[0])) {
+		case "PRIORITY":
+			index.Priority, _ = strconv.Atoi(value)
+		case "LENGTH":
+			index.Length, _ = strconv.Atoi(value)
+		case "CLASS":
+			index.Class = strings.ToUpper(value)
+			if index.Class == "UNIQUE" {
+				index.Unique, index.Class = true, ""
+			}
+		}
+	}
+	//与gorm未命名索引的规则一致
+	if index.Name == "" {
+		index.Name = "idx_" + tableName + "_" + columnName
+	}
+	return index
+}
+
+//splitXORMTag 按空格拆分xorm的tag,引号和括号中的空格不拆分
+func splitXORMTag(tag string) []string {
+	var items []string
+	var buf strings.Builder
+	inQuote, depth := false, 0
+	for _, r := range tag {
+		switch {
+		case r == '\'':
+			inQuote = !inQuote
+		case r == '(' && !inQuote:
+			depth++
+		case r == ')' && !inQuote && depth > 0:
+			depth--
+		case unicode.IsSpace(r) && !inQuote && depth == 0:
+			if buf.Len() > 0 {
+				items = append(items, buf.String())
+				buf.Reset()
+			}
+			continue
+		}
+		buf.WriteRune(r)
+	}
+	if buf.Len() > 0 {
+		items = append(items, buf.String())
+	}
+	return items
+}
+
+//xormType 获取xorm的tag中的字段类型
+func xormType(items []string) string {
+	for i, item := range items {
+		if i > 0 && strings.EqualFold(items[i-1], "default") {
+			continue
+		}
+		name := strings.ToLower(item)
+		if open := strings.Index(name, "("); open >= 0 {
+			name = name[:open]
+		}
+		if inStrings(name, mysqlDataTypes()) {
+			return item
+		}
+	}
+	return ""
+}
+
+//mysqlDataTypes 所有能够识别的MySQL类型
+func mysqlDataTypes() []string {
+	var dataTypes []string
+	for _, rule := range mysqlTypeRules(&Options{}) {
+		if rule.DataType != "" {
+			dataTypes = append(dataTypes, strings.Split(rule.DataType, ",")...)
+		}
+	}
+	return dataTypes
+}
+
+//goSQLTypes golang类型对应的MySQL类型
+var goSQLTypes = map[string]string{
+	"bool":            "tinyint(1)",
+	"int8":            "tinyint",
+	"uint8":           "tinyint unsigned",
+	"byte":            "tinyint unsigned",
+	"int16":           "smallint",
+	"uint16":          "smallint unsigned",
+	"int":             "int",
+	"int32":           "int",
+	"rune":            "int",
+	"uint":            "int unsigned",
+	"uint32":          "int unsigned",
+	"int64":           "bigint",
+	"uint64":          "bigint unsigned",
+	"float32":         "float",
+	"float64":         "double",
+	"string":          "varchar(255)",
+	"[]byte":          "blob",
+	"time.Time":       "datetime",
+	"json.RawMessage": "json",
+	"datatypes.JSON":  "json",
+	"datatypes.Date":  "date",
+	"decimal.Decimal": "decimal(20,6)",
+	"uuid.UUID":       "char(36)",
+}
+
+//goNullSQLTypes 可以为空的类型对应的MySQL类型,如sql.NullInt64、null.Int
+var goNullSQLTypes = map[string]string{
+	"NullString":  "varchar(255)",
+	"NullInt64":   "bigint",
+	"NullInt32":   "int",
+	"NullInt16":   "smallint",
+	"NullByte":    "tinyint unsigned",
+	"NullFloat64": "double",
+	"NullBool":    "tinyint(1)",
+	"NullTime":    "datetime",
+	"String":      "varchar(255)",
+	"Int":         "bigint",
+	"Float":       "double",
+	"Bool":        "tinyint(1)",
+	"Time":        "datetime",
+	"DeletedAt":   "datetime(3)",
+}
+
+//sqlType 根据golang类型获取MySQL类型,返回的nullable表示该类型是否可以表示NULL
+func (p *structParser) sqlType(goType string) (sqlType string, nullable bool, ok bool) {
+	if strings.HasPrefix(goType, "*") {
+		sqlType, _, ok = p.sqlType(goType[1:])
+		return sqlType, true, ok
+	}
+	if strings.HasPrefix(goType, "sql.Null[") && strings.HasSuffix(goType, "]") {
+		sqlType, _, ok = p.sqlType(goType[len("sql.Null[") : len(goType)-1])
+		return sqlType, true, ok
+	}
+	if sqlType, ok := goSQLTypes[goType]; ok {
+		return sqlType, false, true
+	}
+	if i := strings.Index(goType, "."); i >= 0 {
+		switch goType[:i] {
+		case "sql", "nulltype", "null", "gorm":
+			if sqlType, ok := goNullSQLTypes[goType[i+1:]]; ok {
+				return sqlType, true, true
+			}
+		}
+	}
+	if values, ok := p.enums[goType]; ok && p.namedTypes[goType] == "string" {
+		return "enum(" + quoteSQLValues(values) + ")", false, true
+	}
+	if values, ok := p.stringLists[lowerFirst(goType)+"Values"]; ok && p.namedTypes[goType] == "uint64" {
+		return "set(" + quoteSQLValues(values) + ")", false, true
+	}
+	if underlying, ok := p.namedTypes[goType]; ok && underlying != goType {
+		return p.sqlType(underlying)
+	}
+	return "", false, false
+}
+
+func quoteSQLValues(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		quoted = append(quoted, quoteMySQLString(value))
+	}
+	return strings.Join(quoted, ",")
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return string(unicode.ToLower(rune(s[0]))) + s[1:]
+}
+
+//isIntegerSQLType 是否为整型
+func isIntegerSQLType(sqlType string) bool {
+	name := strings.ToLower(strings.TrimSpace(sqlType))
+	if i := strings.IndexAny(name, "( "); i >= 0 {
+		name = name[:i]
+	}
+	switch name {
+	case "tinyint", "smallint", "mediumint", "int", "integer", "bigint":
+		return true
+	}
+	return false
+}
+
+//gormModelColumns gorm.Model中的字段
+func gormModelColumns(tableName string) []structColumn {
+	return []structColumn{
+		{Name: "id", Type: "bigint unsigned", IsPrimaryKey: true, AutoIncrement: true},
+		{Name: "created_at", Type: "datetime(3)", IsNullAble: true},
+		{Name: "updated_at", Type: "datetime(3)", IsNullAble: true},
+		{Name: "deleted_at", Type: "datetime(3)", IsNullAble: true, Indexes: []structIndex{{Name: "idx_" + tableName + "_deleted_at", Priority: 10}}},
+	}
+}
+
+//definition 生成字段定义,交给DDL解析器解析
+func (col structColumn) definition() string {
+	parts := []string{quoteMySQL(col.Name), col.Type}
+	if !col.IsNullAble {
+		parts = append(parts, "NOT NULL")
+	}
+	if col.HasDefault {
+		parts = append(parts, "DEFAULT "+structDefault(col.Default))
+	}
+	if col.AutoIncrement {
+		parts = append(parts, "AUTO_INCREMENT")
+	}
+	if col.Comment != "" {
+		parts = append(parts, "COMMENT "+quoteMySQLString(col.Comment))
+	}
+	return strings.Join(parts, " ")
+}
+
+//structDefault 处理tag中的默认值,已经加上引号的值、数字和CURRENT_TIMESTAMP之类的表达式保持不变
+func structDefault(value string) string {
+	if len(value) > 1 && strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'") {
+		return value
+	}
+	if isDDLNumber(value) || defaultExpressionRegexp.MatchString(value) || strings.Contains(value, "(") {
+		return value
+	}
+	return quoteMySQLString(value)
+}
+
+//parseColumnDefinition 解析单个字段定义
+func parseColumnDefinition(definition string) (ColumnSchema, error) {
+	tokens, err := tokenizeDDL(definition)
+	if err != nil {
+		return ColumnSchema{}, err
+	}
+	p := &ddlParser{src: definition, tokens: tokens}
+	col, err := p.parseColumn()
+	if err != nil {
+		return col, err
+	}
+	if !p.eof() {
+		return col, fmt.Errorf("无法解析字段定义: %s", definition)
+	}
+	return col, nil
+}
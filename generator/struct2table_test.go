@@ -0,0 +1,86 @@
+package generator
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+//TestParseStructFilesForeignKeyOrder 被引用的表定义在后面的文件中时,建表顺序仍然要先建被引用的表
+func TestParseStructFilesForeignKeyOrder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "struct2table")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	writeFile(t, filepath.Join(dir, "a_comment.go"), []byte(`package models
+
+type Comment struct {
+	ID     uint64 `+"`gorm:\"column:id;primaryKey;autoIncrement\"`"+`
+	PostID uint64 `+"`gorm:\"column:post_id;not null\"`"+`
+	Post   *Post  `+"`gorm:\"foreignKey:PostID\"`"+`
+}
+`))
+	writeFile(t, filepath.Join(dir, "b_post.go"), []byte(`package models
+
+type Post struct {
+	ID     uint64 `+"`gorm:\"column:id;primaryKey;autoIncrement\"`"+`
+	UserID uint64 `+"`gorm:\"column:user_id;not null\"`"+`
+	User   *User  `+"`gorm:\"foreignKey:UserID\"`"+`
+}
+`))
+	writeFile(t, filepath.Join(dir, "c_user.go"), []byte(`package models
+
+type User struct {
+	ID uint64 `+"`gorm:\"column:id;primaryKey;autoIncrement\"`"+`
+}
+`))
+	schema, warnings, err := ParseStructFiles([]string{dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) > 0 {
+		t.Errorf("警告: %v", warnings)
+	}
+	var names []string
+	for _, table := range schema.Tables {
+		names = append(names, table.Schema.TableName)
+	}
+	if want := []string{"user", "post", "comment"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("got %v, want %v", names, want)
+	}
+}
+
+func TestSortTablesByForeignKey(t *testing.T) {
+	table := func(name string, refs ...string) DDLTable {
+		table := DDLTable{Schema: TableSchema{TableName: name}}
+		for _, ref := range refs {
+			table.ForeignKeys = append(table.ForeignKeys, ForeignKey{TableName: name, RefTableName: ref})
+		}
+		return table
+	}
+	tests := []struct {
+		tables []DDLTable
+		want   []string
+	}{
+		//没有依赖关系时保持原来的顺序
+		{[]DDLTable{table("b"), table("a")}, []string{"b", "a"}},
+		//引用自身和不在本次生成的表不影响顺序
+		{[]DDLTable{table("b", "b", "x"), table("a")}, []string{"b", "a"}},
+		{[]DDLTable{table("c", "a", "b"), table("b", "a"), table("a")}, []string{"a", "b", "c"}},
+		//循环引用时先输出名称最小的表
+		{[]DDLTable{table("d"), table("c", "b"), table("b", "c"), table("e", "b")}, []string{"d", "b", "c", "e"}},
+	}
+	for _, test := range tests {
+		sortTablesByForeignKey(test.tables)
+		var names []string
+		for _, table := range test.tables {
+			names = append(names, table.Schema.TableName)
+		}
+		if !reflect.DeepEqual(names, test.want) {
+			t.Errorf("got %v, want %v", names, test.want)
+		}
+	}
+}
@@ -0,0 +1,356 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: models.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	wrapperspb "google.golang.org/protobuf/types/known/wrapperspb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Item_Status int32
+
+const (
+	Item_STATUS_UNSPECIFIED Item_Status = 0
+	Item_STATUS_ON          Item_Status = 1
+	Item_STATUS_OFF         Item_Status = 2
+	Item_STATUS_EMPTY       Item_Status = 3
+)
+
+// Enum value maps for Item_Status.
+var (
+	Item_Status_name = map[int32]string{
+		0: "STATUS_UNSPECIFIED",
+		1: "STATUS_ON",
+		2: "STATUS_OFF",
+		3: "STATUS_EMPTY",
+	}
+	Item_Status_value = map[string]int32{
+		"STATUS_UNSPECIFIED": 0,
+		"STATUS_ON":          1,
+		"STATUS_OFF":         2,
+		"STATUS_EMPTY":       3,
+	}
+)
+
+func (x Item_Status) Enum() *Item_Status {
+	p := new(Item_Status)
+	*p = x
+	return p
+}
+
+func (x Item_Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Item_Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_models_proto_enumTypes[0].Descriptor()
+}
+
+func (Item_Status) Type() protoreflect.EnumType {
+	return &file_models_proto_enumTypes[0]
+}
+
+func (x Item_Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Item_Status.Descriptor instead.
+func (Item_Status) EnumDescriptor() ([]byte, []int) {
+	return file_models_proto_rawDescGZIP(), []int{0, 0}
+}
+
+type Item_Kind int32
+
+const (
+	Item_KIND_UNSPECIFIED Item_Kind = 0
+	Item_KIND_A           Item_Kind = 1
+	Item_KIND_B           Item_Kind = 2
+)
+
+// Enum value maps for Item_Kind.
+var (
+	Item_Kind_name = map[int32]string{
+		0: "KIND_UNSPECIFIED",
+		1: "KIND_A",
+		2: "KIND_B",
+	}
+	Item_Kind_value = map[string]int32{
+		"KIND_UNSPECIFIED": 0,
+		"KIND_A":           1,
+		"KIND_B":           2,
+	}
+)
+
+func (x Item_Kind) Enum() *Item_Kind {
+	p := new(Item_Kind)
+	*p = x
+	return p
+}
+
+func (x Item_Kind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Item_Kind) Descriptor() protoreflect.EnumDescriptor {
+	return file_models_proto_enumTypes[1].Descriptor()
+}
+
+func (Item_Kind) Type() protoreflect.EnumType {
+	return &file_models_proto_enumTypes[1]
+}
+
+func (x Item_Kind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Item_Kind.Descriptor instead.
+func (Item_Kind) EnumDescriptor() ([]byte, []int) {
+	return file_models_proto_rawDescGZIP(), []int{0, 1}
+}
+
+type Item struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Id            uint64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                  `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Title         *wrapperspb.StringValue `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Status        Item_Status             `protobuf:"varint,4,opt,name=status,proto3,enum=models.Item_Status" json:"status,omitempty"`
+	Kind          Item_Kind               `protobuf:"varint,5,opt,name=kind,proto3,enum=models.Item_Kind" json:"kind,omitempty"`
+	Flags         string                  `protobuf:"bytes,6,opt,name=flags,proto3" json:"flags,omitempty"`
+	Price         string                  `protobuf:"bytes,7,opt,name=price,proto3" json:"price,omitempty"`
+	Weight        *wrapperspb.FloatValue  `protobuf:"bytes,8,opt,name=weight,proto3" json:"weight,omitempty"`
+	Amount        *wrapperspb.Int32Value  `protobuf:"bytes,9,opt,name=amount,proto3" json:"amount,omitempty"`
+	Data          *wrapperspb.BytesValue  `protobuf:"bytes,10,opt,name=data,proto3" json:"data,omitempty"`
+	CreatedAt     *timestamppb.Timestamp  `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	DeletedAt     *timestamppb.Timestamp  `protobuf:"bytes,12,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Item) Reset() {
+	*x = Item{}
+	mi := &file_models_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Item) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Item) ProtoMessage() {}
+
+func (x *Item) ProtoReflect() protoreflect.Message {
+	mi := &file_models_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Item.ProtoReflect.Descriptor instead.
+func (*Item) Descriptor() ([]byte, []int) {
+	return file_models_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Item) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Item) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Item) GetTitle() *wrapperspb.StringValue {
+	if x != nil {
+		return x.Title
+	}
+	return nil
+}
+
+func (x *Item) GetStatus() Item_Status {
+	if x != nil {
+		return x.Status
+	}
+	return Item_STATUS_UNSPECIFIED
+}
+
+func (x *Item) GetKind() Item_Kind {
+	if x != nil {
+		return x.Kind
+	}
+	return Item_KIND_UNSPECIFIED
+}
+
+func (x *Item) GetFlags() string {
+	if x != nil {
+		return x.Flags
+	}
+	return ""
+}
+
+func (x *Item) GetPrice() string {
+	if x != nil {
+		return x.Price
+	}
+	return ""
+}
+
+func (x *Item) GetWeight() *wrapperspb.FloatValue {
+	if x != nil {
+		return x.Weight
+	}
+	return nil
+}
+
+func (x *Item) GetAmount() *wrapperspb.Int32Value {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *Item) GetData() *wrapperspb.BytesValue {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *Item) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Item) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+var File_models_proto protoreflect.FileDescriptor
+
+const file_models_proto_rawDesc = "" +
+	"\n" +
+	"\fmodels.proto\x12\x06models\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1egoogle/protobuf/wrappers.proto\"\xf8\x04\n" +
+	"\x04Item\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x122\n" +
+	"\x05title\x18\x03 \x01(\v2\x1c.google.protobuf.StringValueR\x05title\x12+\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x13.models.Item.StatusR\x06status\x12%\n" +
+	"\x04kind\x18\x05 \x01(\x0e2\x11.models.Item.KindR\x04kind\x12\x14\n" +
+	"\x05flags\x18\x06 \x01(\tR\x05flags\x12\x14\n" +
+	"\x05price\x18\a \x01(\tR\x05price\x123\n" +
+	"\x06weight\x18\b \x01(\v2\x1b.google.protobuf.FloatValueR\x06weight\x123\n" +
+	"\x06amount\x18\t \x01(\v2\x1b.google.protobuf.Int32ValueR\x06amount\x12/\n" +
+	"\x04data\x18\n" +
+	" \x01(\v2\x1b.google.protobuf.BytesValueR\x04data\x129\n" +
+	"\n" +
+	"created_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"deleted_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\"Q\n" +
+	"\x06Status\x12\x16\n" +
+	"\x12STATUS_UNSPECIFIED\x10\x00\x12\r\n" +
+	"\tSTATUS_ON\x10\x01\x12\x0e\n" +
+	"\n" +
+	"STATUS_OFF\x10\x02\x12\x10\n" +
+	"\fSTATUS_EMPTY\x10\x03\"4\n" +
+	"\x04Kind\x12\x14\n" +
+	"\x10KIND_UNSPECIFIED\x10\x00\x12\n" +
+	"\n" +
+	"\x06KIND_A\x10\x01\x12\n" +
+	"\n" +
+	"\x06KIND_B\x10\x02B\x1bZ\x19example.com/protocheck/pbb\x06proto3"
+
+var (
+	file_models_proto_rawDescOnce sync.Once
+	file_models_proto_rawDescData []byte
+)
+
+func file_models_proto_rawDescGZIP() []byte {
+	file_models_proto_rawDescOnce.Do(func() {
+		file_models_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_models_proto_rawDesc), len(file_models_proto_rawDesc)))
+	})
+	return file_models_proto_rawDescData
+}
+
+var file_models_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_models_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_models_proto_goTypes = []any{
+	(Item_Status)(0),               // 0: models.Item.Status
+	(Item_Kind)(0),                 // 1: models.Item.Kind
+	(*Item)(nil),                   // 2: models.Item
+	(*wrapperspb.StringValue)(nil), // 3: google.protobuf.StringValue
+	(*wrapperspb.FloatValue)(nil),  // 4: google.protobuf.FloatValue
+	(*wrapperspb.Int32Value)(nil),  // 5: google.protobuf.Int32Value
+	(*wrapperspb.BytesValue)(nil),  // 6: google.protobuf.BytesValue
+	(*timestamppb.Timestamp)(nil),  // 7: google.protobuf.Timestamp
+}
+var file_models_proto_depIdxs = []int32{
+	3, // 0: models.Item.title:type_name -> google.protobuf.StringValue
+	0, // 1: models.Item.status:type_name -> models.Item.Status
+	1, // 2: models.Item.kind:type_name -> models.Item.Kind
+	4, // 3: models.Item.weight:type_name -> google.protobuf.FloatValue
+	5, // 4: models.Item.amount:type_name -> google.protobuf.Int32Value
+	6, // 5: models.Item.data:type_name -> google.protobuf.BytesValue
+	7, // 6: models.Item.created_at:type_name -> google.protobuf.Timestamp
+	7, // 7: models.Item.deleted_at:type_name -> google.protobuf.Timestamp
+	8, // [8:8] is the sub-list for method output_type
+	8, // [8:8] is the sub-list for method input_type
+	8, // [8:8] is the sub-list for extension type_name
+	8, // [8:8] is the sub-list for extension extendee
+	0, // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_models_proto_init() }
+func file_models_proto_init() {
+	if File_models_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_models_proto_rawDesc), len(file_models_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_models_proto_goTypes,
+		DependencyIndexes: file_models_proto_depIdxs,
+		EnumInfos:         file_models_proto_enumTypes,
+		MessageInfos:      file_models_proto_msgTypes,
+	}.Build()
+	File_models_proto = out.File
+	file_models_proto_goTypes = nil
+	file_models_proto_depIdxs = nil
+}
@@ -0,0 +1,201 @@
+package generator
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+//TypeRule 类型映射规则,所有不为空的匹配条件都满足时使用该规则
+type TypeRule struct {
+	//DataType 匹配DATA_TYPE,不区分大小写,多个类型用逗号分隔,如int,integer
+	DataType string
+	//ColumnType 匹配完整的COLUMN_TYPE,不区分大小写,支持通配符,如tinyint(1)、binary(*)
+	ColumnType string
+	//Nullable 匹配字段是否允许为空,为nil时不限制
+	Nullable *bool
+	//Unsigned 匹配字段是否为无符号整型,为nil时不限制
+	Unsigned *bool
+	//Column 匹配字段名的正则表达式,如_uuid$
+	Column string
+	//Table 匹配表名,支持通配符,如order_*
+	Table string
+
+	//GoType 转换后的golang类型,如decimal.Decimal
+	GoType string
+	//Import GoType所在的包,如github.com/shopspring/decimal
+	Import string
+	//NullableType 字段允许为空且需要转换为可为空的类型时使用的类型,为空时按NullStrategy的规则转换GoType
+	NullableType string
+
+	column *regexp.Regexp
+}
+
+//compile 检查规则并编译字段名的正则表达式
+func (r *TypeRule) compile() error {
+	if r.GoType == "" {
+		return fmt.Errorf("没有指定转换后的类型")
+	}
+	if r.ColumnType != "" {
+		if _, err := path.Match(r.ColumnType, ""); err != nil {
+			return fmt.Errorf("column_type格式错误:%v", err)
+		}
+	}
+	if r.Table != "" {
+		if _, err := path.Match(r.Table, ""); err != nil {
+			return fmt.Errorf("table格式错误:%v", err)
+		}
+	}
+	if r.Column != "" {
+		column, err := regexp.Compile(r.Column)
+		if err != nil {
+			return fmt.Errorf("column格式错误:%v", err)
+		}
+		r.column = column
+	}
+	return nil
+}
+
+//Match 判断字段是否满足规则
+func (r TypeRule) Match(col ColumnSchema) bool {
+	if r.DataType != "" {
+		matched := false
+		for _, dataType := range strings.Split(r.DataType, ",") {
+			if strings.EqualFold(strings.TrimSpace(dataType), col.DataType) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if r.ColumnType != "" {
+		if ok, _ := path.Match(strings.ToLower(r.ColumnType), strings.ToLower(col.ColumnType)); !ok {
+			return false
+		}
+	}
+	if r.Nullable != nil && *r.Nullable != (col.IsNullAble == "YES") {
+		return false
+	}
+	if r.Unsigned != nil && *r.Unsigned != strings.Contains(strings.ToLower(col.ColumnType), "unsigned") {
+		return false
+	}
+	if r.Column != "" {
+		if r.column != nil {
+			if !r.column.MatchString(col.ColumnName) {
+				return false
+			}
+		} else if ok, _ := regexp.MatchString(r.Column, col.ColumnName); !ok {
+			return false
+		}
+	}
+	if r.Table != "" {
+		if ok, _ := path.Match(r.Table, col.TableName); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+//ruleType 根据规则获取字段类型
+func (opts *Options) ruleType(rule TypeRule, isNullAble bool) (goType string, isNullType bool, isExtNullType bool) {
+	if isNullAble && rule.NullableType != "" && opts.nullStrategy() != NullStrategyNone {
+		goType = rule.NullableType
+		return goType, strings.HasPrefix(goType, "sql."), strings.HasPrefix(goType, "nulltype.")
+	}
+	return opts.nullableType(rule.GoType, isNullAble)
+}
+
+//matchTypeRules 使用第一条匹配的规则转换字段类型,没有匹配的规则时返回空字符串
+func (opts *Options) matchTypeRules(rules []TypeRule, col ColumnSchema, isNullAble bool) (goType string, isNullType bool, isExtNullType bool) {
+	for _, rule := range rules {
+		if rule.Match(col) {
+			return opts.ruleType(rule, isNullAble)
+		}
+	}
+	return "", false, false
+}
+
+//applyTypeRules 使用第一条匹配的自定义规则转换字段类型
+func (g *Generator) applyTypeRules(field *Field, col ColumnSchema) {
+	for _, rule := range g.typeRules {
+		if !rule.Match(col) {
+			continue
+		}
+		field.Type, field.IsNullType, field.IsExtNullType = g.opts.ruleType(rule, field.EnableNull)
+		field.Import = rule.Import
+		return
+	}
+}
+
+//mysqlTypeRules MySQL内置的类型映射规则,优先级低于自定义规则
+func mysqlTypeRules(opts *Options) []TypeRule {
+	tinyint, integer := "int8", "int"
+	if opts.UseInt64 {
+		tinyint, integer = "int64", "int64"
+	}
+	var rules []TypeRule
+	if opts.UseUnsigned && !opts.UseInt64 {
+		unsigned := true
+		rules = append(rules,
+			TypeRule{DataType: "tinyint,int1", Unsigned: &unsigned, GoType: "uint8"},
+			TypeRule{DataType: "smallint,mediumint,integer,int,int2,int3,int4,middleint", Unsigned: &unsigned, GoType: "uint"},
+			TypeRule{DataType: "bigint,int8", Unsigned: &unsigned, GoType: "uint64"},
+		)
+	}
+	return append(rules, []TypeRule{
+		{DataType: "tinyint,int1", GoType: tinyint},
+		{DataType: "smallint,mediumint,integer,int,int2,int3,int4,middleint", GoType: integer},
+		{DataType: "bigint,int8", GoType: "int64"},
+		{DataType: "year", GoType: "int16"},
+		{ColumnType: "bit(1)", GoType: "bool"},
+		{DataType: "bit", GoType: "uint64"},
+		{DataType: "float,double,real,decimal,dec,numeric,fixed", GoType: "float64"},
+		{DataType: "bool,boolean", GoType: "bool"},
+		{DataType: "char,varchar,nchar,nvarchar,tinytext,text,mediumtext,longtext,enum,set,json,uuid,inet4,inet6", GoType: "string"},
+		{DataType: "date,datetime,time,timestamp", GoType: "time.Time"},
+		{DataType: "binary,varbinary,tinyblob,blob,mediumblob,longblob,vector", GoType: "[]byte"},
+		//空间类型以WKB格式读取
+		{DataType: "geometry,point,linestring,polygon,multipoint,multilinestring,multipolygon,geometrycollection,geomcollection", GoType: "[]byte"},
+	}...)
+}
+
+//postgresTypeRules PostgreSQL内置的类型映射规则,按udt_name匹配,优先级低于自定义规则
+func postgresTypeRules(opts *Options) []TypeRule {
+	integer := "int"
+	if opts.UseInt64 {
+		integer = "int64"
+	}
+	return []TypeRule{
+		//数组类型的udt_name以下划线开头,使用github.com/lib/pq中的数组类型
+		{DataType: "_int2,_int4,_int8", GoType: "pq.Int64Array"},
+		{DataType: "_float4,_float8,_numeric", GoType: "pq.Float64Array"},
+		{DataType: "_bool", GoType: "pq.BoolArray"},
+		{DataType: "_bytea", GoType: "pq.ByteaArray"},
+		{ColumnType: "_*", GoType: "pq.StringArray"},
+		{DataType: "int2,int4,serial2,serial4", GoType: integer},
+		{DataType: "int8,serial8,oid", GoType: "int64"},
+		{DataType: "float4,float8,numeric,money", GoType: "float64"},
+		{DataType: "bool", GoType: "bool"},
+		{DataType: "char,bpchar,varchar,text,name,citext,uuid,json,jsonb,xml,inet,cidr,macaddr,macaddr8,interval,bit,varbit,tsvector,tsquery,enum", GoType: "string"},
+		{DataType: "date,time,timetz,timestamp,timestamptz", GoType: "time.Time"},
+		{DataType: "bytea", GoType: "[]byte"},
+	}
+}
+
+//sqliteTypeRules SQLite内置的类型映射规则,按声明的类型匹配,优先级低于自定义规则
+func sqliteTypeRules() []TypeRule {
+	return []TypeRule{
+		{DataType: "date,datetime,timestamp,time", GoType: "time.Time"},
+		{DataType: "bool,boolean", GoType: "bool"},
+		//类型亲和性:包含INT的为INTEGER,包含CHAR、CLOB、TEXT的为TEXT,包含BLOB的为BLOB,其余为REAL或NUMERIC
+		{ColumnType: "*int*", GoType: "int64"},
+		{ColumnType: "*char*", GoType: "string"},
+		{ColumnType: "*clob*", GoType: "string"},
+		{ColumnType: "*text*", GoType: "string"},
+		{ColumnType: "*blob*", GoType: "[]byte"},
+		{ColumnType: "*", GoType: "float64"},
+	}
+}
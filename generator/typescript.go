@@ -0,0 +1,248 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+//DefaultTypeScriptTemplate 默认的TypeScript模板
+const DefaultTypeScriptTemplate = `{{- range $i, $t := .Types}}
+{{- if $i}}
+{{end}}
+{{- range .Comment}}
+{{.}}
+{{- end}}
+export type {{.Name}} = {{.Type}};
+{{- end}}
+{{- range $i, $t := .Interfaces}}
+{{- if or $i $.Types}}
+{{end}}
+{{- range .Comment}}
+{{.}}
+{{- end}}
+export interface {{.Name}} {
+{{- range .Properties}}
+{{- range .Comment}}
+  {{.}}
+{{- end}}
+  {{.Name}}{{if .Optional}}?{{end}}: {{.Type}};
+{{- end}}
+}
+{{- end}}
+`
+
+//TypeScriptFile 渲染TypeScript模板所需的数据
+type TypeScriptFile struct {
+	//Types enum和set字段生成的类型对应的联合类型
+	Types []TypeScriptType
+	//Interfaces 每张表对应一个interface
+	Interfaces []TypeScriptInterface
+}
+
+//TypeScriptType type声明
+type TypeScriptType struct {
+	Name    string
+	Type    string
+	Comment []string
+}
+
+//TypeScriptInterface 表对应的interface
+type TypeScriptInterface struct {
+	//Name 与struct名称相同
+	Name string
+	//Comment JSDoc格式的表注释
+	Comment []string
+	//Properties 属性,名称与json tag相同
+	Properties []TypeScriptProperty
+}
+
+//TypeScriptProperty interface中的属性
+type TypeScriptProperty struct {
+	Name     string
+	Type     string
+	Optional bool
+	Comment  []string
+}
+
+//TypeScriptFile 生成包含所有表的TypeScript数据
+func (g *Generator) TypeScriptFile(tables []Table) TypeScriptFile {
+	var file TypeScriptFile
+	structNames := make(map[string]bool, len(tables))
+	for _, table := range tables {
+		structNames[g.StructName(table.OriginName)] = true
+	}
+	for _, table := range tables {
+		data := g.StructData(table)
+		enumTypes := make(map[string]bool, len(data.Enums))
+		for _, enum := range data.Enums {
+			enumTypes[enum.GoName] = true
+			if enum.IsSet {
+				//set类型没有实现json.Marshaler,序列化为数字
+				file.Types = append(file.Types, TypeScriptType{Name: enum.GoName, Type: "number", Comment: jsDoc(enum.Comment)})
+				continue
+			}
+			file.Types = append(file.Types, TypeScriptType{Name: enum.GoName, Type: typeScriptUnion(enum.Values), Comment: jsDoc(enum.Comment)})
+		}
+		tsInterface := TypeScriptInterface{
+			Name:    data.GoName,
+			Comment: jsDoc(data.Comment),
+		}
+		for _, field := range data.Fields {
+			name, optional, ok := jsonName(field.TagValue("json"), field.GoName)
+			if !ok {
+				continue
+			}
+			property := TypeScriptProperty{
+				Name:     typeScriptPropertyName(name),
+				Type:     g.typeScriptType(field, enumTypes),
+				Optional: optional,
+				Comment:  jsDoc(field.Comment),
+			}
+			tsInterface.Properties = append(tsInterface.Properties, property)
+		}
+		for _, association := range data.Associations {
+			refType := strings.TrimLeft(association.Type, "*[]")
+			if !structNames[refType] {
+				continue
+			}
+			var jsonTag string
+			for _, tag := range association.Tags {
+				if tag.Key == "json" {
+					jsonTag = tag.Value
+				}
+			}
+			name, optional, ok := jsonName(jsonTag, association.GoName)
+			if !ok {
+				continue
+			}
+			property := TypeScriptProperty{Name: typeScriptPropertyName(name), Type: refType, Optional: optional}
+			if association.IsBelongsTo {
+				if !optional {
+					property.Type += " | null"
+				}
+			} else {
+				property.Type += "[]"
+				if !optional {
+					property.Type += " | null"
+				}
+			}
+			tsInterface.Properties = append(tsInterface.Properties, property)
+		}
+		file.Interfaces = append(file.Interfaces, tsInterface)
+	}
+	return file
+}
+
+//typeScriptType 字段的golang类型序列化为json后对应的TypeScript类型,允许为空的字段可以为null
+func (g *Generator) typeScriptType(field StructField, enumTypes map[string]bool) string {
+	t := fieldJSONType(field, enumTypes)
+	tsType := typeScriptBaseType(t, field.Field, enumTypes)
+	if t.Nullable {
+		tsType += " | null"
+	}
+	return tsType
+}
+
+//typeScriptBaseType 不为null的值对应的TypeScript类型,字符串类型的enum字段为联合类型
+func typeScriptBaseType(t jsonType, field Field, enumTypes map[string]bool) string {
+	switch t.Kind {
+	case "string", "time", "bytes", "decimal":
+		return "string"
+	case "int", "uint", "float":
+		return "number"
+	case "bool":
+		return "boolean"
+	case "enum", "set":
+		if enumTypes[t.GoType] {
+			return t.GoType
+		}
+		values := make([]EnumValue, 0, len(field.EnumValues))
+		for _, value := range field.EnumValues {
+			values = append(values, EnumValue{Value: value})
+		}
+		return typeScriptUnion(values)
+	case "array":
+		return typeScriptBaseType(*t.Elem, Field{}, nil) + "[]"
+	case "object":
+		return "{ " + t.Member + ": " + typeScriptBaseType(*t.Elem, field, enumTypes) + "; Valid: boolean }"
+	}
+	return "unknown"
+}
+
+//typeScriptUnion 由enum的值组成的联合类型,如"on" | "off"
+func typeScriptUnion(values []EnumValue) string {
+	items := make([]string, 0, len(values))
+	for _, value := range values {
+		items = append(items, strconv.Quote(value.Value))
+	}
+	if len(items) == 0 {
+		return "string"
+	}
+	return strings.Join(items, " | ")
+}
+
+//jsonName 根据json tag确定序列化后的名称,tag为-时返回false
+func jsonName(tag, goName string) (name string, omitEmpty bool, ok bool) {
+	if tag == "-" {
+		return "", false, false
+	}
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	if name == "" {
+		name = goName
+	}
+	for _, option := range parts[1:] {
+		if option == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	return name, omitEmpty, true
+}
+
+//typeScriptPropertyName 不是合法标识符的属性名需要加上引号
+func typeScriptPropertyName(name string) string {
+	for i, r := range name {
+		if !(r == '_' || r == '$' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || i > 0 && r >= '0' && r <= '9') {
+			return strconv.Quote(name)
+		}
+	}
+	return name
+}
+
+//jsDoc 将注释转换为JSDoc,多行注释每行以*开头
+func jsDoc(comment string) []string {
+	comment = strings.TrimSpace(strings.Replace(comment, "*/", "*\\/", -1))
+	if comment == "" {
+		return nil
+	}
+	lines := strings.Split(strings.Replace(comment, "\r\n", "\n", -1), "\n")
+	if len(lines) == 1 {
+		return []string{"/** " + lines[0] + " */"}
+	}
+	doc := []string{"/**"}
+	for _, line := range lines {
+		doc = append(doc, strings.TrimRight(" * "+line, " "))
+	}
+	return append(doc, " */")
+}
+
+//typeScriptFileName TypeScript文件名,如models.ts
+func (g *Generator) typeScriptFileName() string {
+	return g.opts.PackageName + ".ts"
+}
+
+//RenderTypeScript 生成包含所有表的TypeScript interface
+func (g *Generator) RenderTypeScript(tables []Table) ([]byte, error) {
+	tpl, err := template.New("typescript").Funcs(g.templateFuncs()).Parse(DefaultTypeScriptTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("解析模板失败:%v", err)
+	}
+	buf := bytes.NewBufferString("")
+	if err := tpl.Execute(buf, g.TypeScriptFile(tables)); err != nil {
+		return nil, fmt.Errorf("渲染TypeScript失败:%v", err)
+	}
+	return append(bytes.Trim(buf.Bytes(), "\n"), '\n'), nil
+}
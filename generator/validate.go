@@ -0,0 +1,175 @@
+package generator
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+//columnLength 字段的长度和小数位数,字符串类型为最大字符数,数值类型为精度
+func columnLength(col ColumnSchema) (length, decimalDigits int) {
+	if col.CharacterMaximumLength.Valid {
+		length = int(col.CharacterMaximumLength.Int64)
+	} else if col.NumericPrecision.Valid {
+		length = int(col.NumericPrecision.Int64)
+	}
+	if col.NumericScale.Valid {
+		decimalDigits = int(col.NumericScale.Int64)
+	}
+	return length, decimalDigits
+}
+
+//validateRules 根据字段信息生成go-playground/validator的规则:
+//开启ValidateRequired(默认开启)且不允许为空、没有默认值时为required,字符串为max=长度,enum为oneof,
+//整型根据数据库类型的范围限制golang类型中超出的部分,无符号数为gte=0,decimal根据精度限制范围
+func (g *Generator) validateRules(field Field) []string {
+	var rules []string
+	kind := validateKind(field)
+	if g.opts.ValidateRequired && !field.EnableNull && !field.HasDefault && !field.IsAutoIncrement && kind != "bool" {
+		rules = append(rules, "required")
+	}
+	switch kind {
+	case "string", "bytes":
+		if len(field.EnumValues) > 0 {
+			if oneOf, ok := validateOneOf(field.EnumValues); ok && !field.IsSet {
+				rules = append(rules, "oneof="+oneOf)
+			}
+		} else if field.Length > 0 && !isDecimalType(field.OriginType) {
+			rules = append(rules, "max="+strconv.Itoa(field.Length))
+		}
+	case "int", "uint", "float":
+		unsigned := field.IsUnsigned && kind != "uint"
+		if isDecimalType(field.OriginType) && field.Length > 0 && field.Length >= field.DecimalDigits {
+			//decimal(5,2)的范围为(-1000,1000)
+			bound := "1" + strings.Repeat("0", field.Length-field.DecimalDigits)
+			if unsigned {
+				rules = append(rules, "gte=0")
+			} else {
+				rules = append(rules, "gt=-"+bound)
+			}
+			rules = append(rules, "lt="+bound)
+		} else if bits := integerBits(g.dbType(), field.DataType); bits > 0 && kind != "float" && len(field.EnumValues) == 0 {
+			rules = append(rules, integerRangeRules(bits, field.IsUnsigned, strings.TrimPrefix(field.Type, "*"))...)
+		} else if unsigned {
+			rules = append(rules, "gte=0")
+		}
+	}
+	//允许为空的字段为nil时跳过其他规则
+	if field.EnableNull && len(rules) > 0 {
+		rules = append([]string{"omitempty"}, rules...)
+	}
+	return rules
+}
+
+//validateKind 字段的golang类型属于哪一类,sql.NullString等validator无法直接校验的类型返回空字符串
+func validateKind(field Field) string {
+	if field.IsNullType || field.IsExtNullType {
+		return ""
+	}
+	if len(field.EnumValues) > 0 {
+		if field.IsSet {
+			return "uint"
+		}
+		return "string"
+	}
+	switch strings.TrimPrefix(field.Type, "*") {
+	case "string":
+		return "string"
+	case "[]byte":
+		return "bytes"
+	case "bool":
+		return "bool"
+	case "int", "int8", "int16", "int32", "int64":
+		return "int"
+	case "uint", "uint8", "uint16", "uint32", "uint64":
+		return "uint"
+	case "float32", "float64":
+		return "float"
+	}
+	return ""
+}
+
+//integerBits 整型的数据类型对应的位数,不是整型时返回0。SQLite的整型都是64位,不需要限制
+func integerBits(dbType, dataType string) int {
+	if dbType == "sqlite" {
+		return 0
+	}
+	switch strings.ToLower(dataType) {
+	case "tinyint", "int1":
+		return 8
+	case "smallint", "int2", "smallserial", "serial2":
+		return 16
+	case "mediumint", "int3", "middleint":
+		return 24
+	case "int", "integer", "int4", "serial", "serial4":
+		return 32
+	case "bigint", "int8", "bigserial", "serial8":
+		return 64
+	}
+	return 0
+}
+
+//goIntegerBits golang整型的位数,int和uint按64位计算
+var goIntegerBits = map[string]int{
+	"int8": 8, "int16": 16, "int32": 32, "int64": 64, "int": 64,
+	"uint8": 8, "uint16": 16, "uint32": 32, "uint64": 64, "uint": 64,
+}
+
+//integerRange 位数为bits的整型的范围
+func integerRange(bits int, unsigned bool) (min, max *big.Int) {
+	one := big.NewInt(1)
+	if unsigned {
+		max = new(big.Int).Lsh(one, uint(bits))
+		return big.NewInt(0), max.Sub(max, one)
+	}
+	max = new(big.Int).Lsh(one, uint(bits-1))
+	min = new(big.Int).Neg(max)
+	return min, max.Sub(max, one)
+}
+
+//integerRangeRules 数据库整型的范围比golang类型小时生成gte和lte,如smallint对应int时为gte=-32768,lte=32767
+func integerRangeRules(bits int, unsigned bool, goType string) []string {
+	goBits, ok := goIntegerBits[goType]
+	if !ok {
+		return nil
+	}
+	min, max := integerRange(bits, unsigned)
+	goMin, goMax := integerRange(goBits, strings.HasPrefix(goType, "uint"))
+	var rules []string
+	if min.Cmp(goMin) > 0 {
+		rules = append(rules, "gte="+min.String())
+	}
+	if max.Cmp(goMax) < 0 {
+		rules = append(rules, "lte="+max.String())
+	}
+	return rules
+}
+
+//isDecimalType 是否为decimal、numeric等定点数类型
+func isDecimalType(columnType string) bool {
+	columnType = strings.ToLower(columnType)
+	for _, prefix := range []string{"decimal", "numeric", "dec(", "fixed"} {
+		if strings.HasPrefix(columnType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+//validateOneOf 生成oneof的参数,包含空格的值用单引号包围,逗号和竖线需要转义。
+//包含单引号或双引号的值无法表示,此时返回false
+func validateOneOf(values []string) (string, bool) {
+	items := make([]string, 0, len(values))
+	for _, value := range values {
+		if strings.ContainsAny(value, `'"\`) {
+			return "", false
+		}
+		value = strings.Replace(value, ",", "0x2C", -1)
+		value = strings.Replace(value, "|", "0x7C", -1)
+		if value == "" || strings.Contains(value, " ") {
+			value = "'" + value + "'"
+		}
+		items = append(items, value)
+	}
+	return strings.Join(items, " "), true
+}
@@ -3,7 +3,12 @@ module github.com/jiazhoulvke/table2struct
 go 1.13
 
 require (
+	github.com/BurntSushi/toml v0.3.1
 	github.com/go-sql-driver/mysql v1.5.0
 	github.com/jmoiron/sqlx v1.2.0
+	github.com/lib/pq v1.10.9
+	github.com/mattn/go-sqlite3 v1.14.6
+	github.com/pmezard/go-difflib v1.0.0
 	github.com/spf13/pflag v1.0.5
+	gopkg.in/yaml.v2 v2.2.8
 )
@@ -1,719 +1,201 @@
 package main
 
 import (
-	"bytes"
-	"database/sql"
+	"context"
 	"fmt"
-	"go/format"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
-	"unicode"
 
-	_ "github.com/go-sql-driver/mysql"
-	"github.com/jmoiron/sqlx"
+	"github.com/jiazhoulvke/table2struct/generator"
 	flag "github.com/spf13/pflag"
 )
 
 var (
-	db                        *sqlx.DB
-	useInt64                  bool
-	useUnsigned               bool
-	commonInitialisms         = []string{"API", "ASCII", "CPU", "CSS", "DNS", "EOF", "GUID", "HTML", "HTTP", "HTTPS", "ID", "IP", "JSON", "LHS", "QPS", "RAM", "RHS", "RPC", "SLA", "SMTP", "SSH", "TLS", "TTL", "UI", "UID", "UUID", "URI", "URL", "UTF8", "VM", "XML", "XSRF", "XSS"}
-	commonInitialismsReplacer *strings.Replacer
-
-	dbHost      string
-	dbPort      int
-	dbUser      string
-	dbPwd       string
-	dbName      string
-	output      string
-	packageName string
-	tagGORM     bool
-	tagXORM     bool
-	tagSQLX     bool
-	tagGORMType bool
-	tagXORMType bool
-	tagJSON     bool
-	mapping     []string
-	mappingFile string
-	//dbMapping 映射关系
-	dbMapping      map[string]map[string]Mapping
-	query          string
-	tablePrefix    string
-	skipIfNoPrefix bool
-	nullType       bool
-	extNullType    bool
+	opts       = generator.DefaultOptions()
+	output     string
+	query      string
+	configPath string
+	check      bool
+	showDiff   bool
+	strict     bool
+	dumpSchema string
 )
 
-//Mapping 映射
-type Mapping struct {
-	FieldName string
-	FieldType string
-}
-
-//Field 字段
-type Field struct {
-	//Name 字段名
-	Name string
-	//OriginName 原始名称
-	OriginName string
-	//Type 数据类型
-	Type string
-	//OriginType 数据库原始类型
-	OriginType string
-	//Length 最大长度
-	Length int
-	//DecimalDigits 小数位数
-	DecimalDigits int
-	//IsUnsigned 是否为无符号整型
-	IsUnsigned bool
-	//EnableNull 是否允许为空
-	EnableNull bool
-	//IsPrimaryKey 是否是主键
-	IsPrimaryKey bool
-	//IsAutoIncrement 是否是自增字段
-	IsAutoIncrement bool
-	//IsNullType 是否是sql.NullInt64之类的类型
-	IsNullType bool
-	//IsExtNullType 是否是nulltype.NullInt64之类的类型
-	IsExtNullType bool
-	//Default 默认值
-	Default string
-	//Comment 注释
-	Comment string
-}
-
-//Table 表
-type Table struct {
-	Name       string
-	OriginName string
-	Fields     []Field
-	HasTime    bool
-	HasPrefix  bool
-	Comment    string
-}
-
-//TableField 表字段属性
-type TableField struct {
-	Field      string         `db:"Field"`
-	Type       string         `db:"Type"`
-	Collation  sql.NullString `db:"Collation"`
-	Null       sql.NullString `db:"Null"`
-	Key        sql.NullString `db:"Key"`
-	Default    sql.NullString `db:"Default"`
-	Extra      sql.NullString `db:"Extra"`
-	Privileges sql.NullString `db:"Privileges"`
-	Comment    sql.NullString `db:"Comment"`
-}
-
-//TableSchema table
-type TableSchema struct {
-	TableCatalog   string         `db:"TABLE_CATALOG"`
-	TableSchema    string         `db:"TABLE_SCHEMA"`
-	TableName      string         `db:"TABLE_NAME"`
-	TableType      string         `db:"TABLE_TYPE"`
-	Engine         string         `db:"ENGINE"`
-	Version        sql.NullInt64  `db:"VERSION"`
-	RowFormat      sql.NullString `db:"ROW_FORMAT"`
-	TableRows      sql.NullInt64  `db:"TABLE_ROWS"`
-	AvgRowLength   sql.NullInt64  `db:"AVG_ROW_LENGTH"`
-	DataLength     sql.NullInt64  `db:"DATA_LENGTH"`
-	MaxDataLength  sql.NullInt64  `db:"MAX_DATA_LENGTH"`
-	IndexLength    sql.NullInt64  `db:"INDEX_LENGTH"`
-	DataFree       sql.NullInt64  `db:"DATA_FREE"`
-	AutoIncrement  sql.NullInt64  `db:"AUTO_INCREMENT"`
-	CreateTime     sql.NullString `db:"CREATE_TIME"`
-	UpdateTime     sql.NullString `db:"UPDATE_TIME"`
-	CheckTime      sql.NullString `db:"CHECK_TIME"`
-	TableCollation sql.NullString `db:"TABLE_COLLATION"`
-	Checksum       sql.NullInt64  `db:"CHECKSUM"`
-	CreateOptions  sql.NullString `db:"CREATE_OPTIONS"`
-	TableComment   sql.NullString `db:"TABLE_COMMENT"`
-}
-
-//ColumnSchema column
-type ColumnSchema struct {
-	TableCatalog           sql.NullString `db:"TABLE_CATALOG"`
-	TableSchema            string         `db:"TABLE_SCHEMA"`
-	TableName              string         `db:"TABLE_NAME"`
-	ColumnName             string         `db:"COLUMN_NAME"`
-	OrdinalPosition        sql.NullInt64  `db:"ORDINAL_POSITION"`
-	ColumnDefault          sql.NullString `db:"COLUMN_DEFAULT"`
-	IsNullAble             string         `db:"IS_NULLABLE"`
-	DataType               string         `db:"DATA_TYPE"`
-	CharacterMaximumLength sql.NullInt64  `db:"CHARACTER_MAXIMUM_LENGTH"`
-	CharacterOctetLength   sql.NullInt64  `db:"CHARACTER_OCTET_LENGTH"`
-	NumericPrecision       sql.NullInt64  `db:"NUMERIC_PRECISION"`
-	NumericScale           sql.NullInt64  `db:"NUMERIC_SCALE"`
-	DatetimePrecision      sql.NullInt64  `db:"DATETIME_PRECISION"`
-	CharacterSetName       sql.NullString `db:"CHARACTER_SET_NAME"`
-	CollationName          sql.NullString `db:"COLLATION_NAME"`
-	ColumnType             string         `db:"COLUMN_TYPE"`
-	ColumnKey              sql.NullString `db:"COLUMN_KEY"`
-	Extra                  sql.NullString `db:"EXTRA"`
-	Privileges             sql.NullString `db:"PRIVILEGES"`
-	ColumnComment          sql.NullString `db:"COLUMN_COMMENT"`
-	GenerationExpression   string         `db:"GENERATION_EXPRESSION"`
-}
-
 func init() {
-	dbMapping = map[string]map[string]Mapping{
-		"global": make(map[string]Mapping),
-	}
-	var commonInitialismsForReplacer []string
-	for _, initialism := range commonInitialisms {
-		commonInitialismsForReplacer = append(commonInitialismsForReplacer, strings.ToLower(initialism), initialism)
-	}
-	commonInitialismsReplacer = strings.NewReplacer(commonInitialismsForReplacer...)
-
-	flag.BoolVar(&useInt64, "int64", false, "是否将tinyint、smallint等类型也转换int64")
-	flag.BoolVar(&useUnsigned, "unsigned", false, "当表中字段为无符号整型时是否在go中也转换为uint的形式")
-	flag.StringVar(&dbHost, "db_host", "127.0.0.1", "数据库ip地址")
-	flag.IntVar(&dbPort, "db_port", 3306, "数据库端口")
-	flag.StringVar(&dbUser, "db_user", "root", "数据库用户名")
-	flag.StringVar(&dbPwd, "db_pwd", "root", "数据库密码")
-	flag.StringVar(&dbName, "db_name", "", "数据库名")
-	flag.StringVar(&packageName, "package_name", "models", "包名")
+	flag.BoolVar(&opts.UseInt64, "int64", false, "是否将tinyint、smallint等类型也转换int64")
+	flag.BoolVar(&opts.UseUnsigned, "unsigned", false, "当表中字段为无符号整型时是否在go中也转换为uint的形式")
+	flag.StringVar(&opts.DBHost, "db_host", "127.0.0.1", "数据库ip地址")
+	flag.IntVar(&opts.DBPort, "db_port", 3306, "数据库端口")
+	flag.StringVar(&opts.DBUser, "db_user", "root", "数据库用户名")
+	flag.StringVar(&opts.DBPwd, "db_pwd", "root", "数据库密码")
+	flag.StringVar(&opts.DBName, "db_name", "", "数据库名")
+	flag.StringVar(&opts.DBType, "db_type", "mysql", "数据库类型,支持mysql、postgres")
+	flag.StringVar(&opts.DBSchema, "db_schema", "public", "PostgreSQL的schema")
+	flag.StringVar(&opts.DBSSLMode, "db_sslmode", "disable", "PostgreSQL的sslmode")
+	flag.StringVar(&opts.PackageName, "package_name", "models", "包名")
 	flag.StringVar(&output, "output", ".", "输出路径,默认为当前目录")
-	flag.BoolVar(&tagGORM, "tag_gorm", false, "是否生成gorm的tag")
-	flag.BoolVar(&tagGORMType, "tag_gorm_type", true, "是否将type包含进gorm的tag")
-	flag.BoolVar(&tagXORM, "tag_xorm", false, "是否生成xorm的tag")
-	flag.BoolVar(&tagXORMType, "tag_xorm_type", true, "是否将type包含进xorm的tag")
-	flag.BoolVar(&tagSQLX, "tag_sqlx", false, "是否生成sqlx的tag")
-	flag.BoolVar(&tagJSON, "tag_json", true, "是否生成json的tag")
-	flag.StringSliceVar(&mapping, "mapping", []string{}, "强制将字段名转换成指定的名称。如--mapping foo:Bar,则表中叫foo的字段在golang中会强制命名为Bar")
-	flag.StringVar(&mappingFile, "mapping_file", "", "字段名映射文件")
+	flag.BoolVar(&opts.TagGORM, "tag_gorm", false, "是否生成gorm的tag")
+	flag.BoolVar(&opts.TagGORMType, "tag_gorm_type", true, "是否将type包含进gorm的tag")
+	flag.BoolVar(&opts.TagXORM, "tag_xorm", false, "是否生成xorm的tag")
+	flag.BoolVar(&opts.TagXORMType, "tag_xorm_type", true, "是否将type包含进xorm的tag")
+	flag.BoolVar(&opts.TagSQLX, "tag_sqlx", false, "是否生成sqlx的tag")
+	flag.BoolVar(&opts.TagJSON, "tag_json", true, "是否生成json的tag")
+	flag.BoolVar(&opts.TagValidate, "tag_validate", false, "是否根据字段的长度、是否为空等生成go-playground/validator的validate tag")
+	flag.BoolVar(&opts.ValidateRequired, "validate_required", true, "是否为不允许为空且没有默认值的字段生成validate的required规则,自增字段和bool字段除外")
+	flag.StringSliceVar(&opts.Mapping, "mapping", []string{}, "强制将字段名转换成指定的名称。如--mapping foo:Bar,则表中叫foo的字段在golang中会强制命名为Bar")
+	flag.StringVar(&opts.MappingFile, "mapping_file", "", "字段名映射文件")
+	flag.StringToStringVar(&opts.Imports, "import", map[string]string{}, "映射后的类型所在包的导入路径,如--import decimal=github.com/shopspring/decimal")
 	flag.StringVar(&query, "query", "", "查询数据库字段名转换后的golang字段名并立即退出")
-	flag.StringVar(&tablePrefix, "table_prefix", "", "表名前缀")
-	flag.BoolVar(&skipIfNoPrefix, "skip_if_no_prefix", false, "当表名不包含指定前缀时跳过不处理")
-	flag.BoolVar(&nullType, "null_type", false, "当字段允许为空时是否用复合类型(如sql.NullInt64)代替")
-	flag.BoolVar(&extNullType, "ext_null_type", false, "用go-nulltype取代database/sql")
+	flag.StringVar(&opts.TablePrefix, "table_prefix", "", "表名前缀")
+	flag.BoolVar(&opts.SkipIfNoPrefix, "skip_if_no_prefix", false, "当表名不包含指定前缀时跳过不处理")
+	flag.BoolVar(&opts.NullType, "null_type", false, "当字段允许为空时是否用复合类型(如sql.NullInt64)代替")
+	flag.BoolVar(&opts.ExtNullType, "ext_null_type", false, "用go-nulltype取代database/sql")
+	flag.StringVar(&opts.NullStrategy, "null_strategy", "", "允许为空的字段的处理方式:sql、nulltype、pointer、generic(sql.Null[T])、guregu,指定后忽略null_type和ext_null_type")
+	flag.StringVar(&opts.DDLFile, "ddl", "", "从包含CREATE TABLE语句的sql文件中读取表结构,无需连接数据库")
+	flag.StringVar(&opts.SQLiteFile, "sqlite", "", "从SQLite数据库文件中读取表结构")
+	flag.StringVar(&opts.SchemaFile, "from-schema", "", "从--dump-schema保存的json快照中读取表结构,无需连接数据库")
+	flag.StringVar(&dumpSchema, "dump-schema", "", "将读取到的表结构(包括索引和外键)保存为json快照")
+	flag.BoolVar(&opts.Relations, "relations", false, "是否根据外键生成关联字段")
+	flag.BoolVar(&opts.Enums, "enum", false, "是否为enum和set字段生成单独的类型")
+	flag.BoolVar(&opts.Indexes, "indexes", false, "是否读取索引,生成gorm、xorm的索引tag和Indexes方法")
+	flag.BoolVar(&opts.Columns, "columns", false, "是否生成保存表名和字段名的<struct名>Columns变量、Columns和FieldByColumn方法")
+	flag.StringVar(&opts.TemplateFile, "template", "", "自定义的struct模板文件(text/template)")
+	flag.BoolVar(&opts.Repo, "repo", false, "是否生成基于sqlx的增删改查函数(<表名>_repo.go),会同时生成sqlx的tag")
+	flag.BoolVar(&opts.Proto, "proto", false, "是否生成包含所有表的proto文件(<proto_package>.proto)")
+	flag.StringVar(&opts.ProtoPackage, "proto_package", "", "proto文件的package,默认与package_name相同")
+	flag.StringVar(&opts.ProtoGoPackage, "proto_go_package", "", "proto文件的go_package,也是ToProto和FromProto中导入的包")
+	flag.BoolVar(&opts.ProtoConvert, "proto_convert", false, "是否生成struct与message互相转换的ToProto和FromProto方法(<表名>_proto.go),需要指定proto_go_package")
+	flag.BoolVar(&opts.TypeScript, "typescript", false, "是否生成包含所有表的TypeScript interface(<package_name>.ts),属性名与json tag相同")
+	flag.StringVar(&opts.OpenAPI, "openapi", "", "生成包含所有表的OpenAPI文档(openapi.yaml或openapi.json),可选值为yaml、json")
+	flag.BoolVar(&opts.JSONSchema, "json_schema", false, "是否为每张表生成JSON Schema(<表名>.schema.json)")
+	flag.StringVar(&opts.ER, "er", "", "生成包含所有表的ER图(<package_name>.mmd、.puml或.dot),可选值为mermaid、plantuml、dot")
+	flag.BoolVar(&opts.ERInfer, "er_infer", false, "生成ER图时是否根据<表名>_id形式的字段名推断没有外键的关系")
+	flag.StringVar(&opts.Doc, "doc", "", "生成包含所有表的数据字典(<package_name>.md或<package_name>.html),可选值为markdown、html")
+	flag.BoolVar(&check, "check", false, "只检查输出目录中的文件是否与数据库结构一致,不一致时以非0状态退出")
+	flag.BoolVar(&showDiff, "diff", false, "不写入文件,输出生成的代码与输出目录中文件的差异")
+	flag.StringVar(&opts.FallbackType, "fallback_type", "string", "无法识别的数据库类型转换后的类型")
+	flag.BoolVar(&strict, "strict", false, "有警告(如无法识别的类型)时以非0状态退出")
+	flag.StringVar(&configPath, "config", "", "配置文件(yaml或toml),默认读取当前目录中的table2struct.yaml")
 }
 
 func main() {
-	flag.Parse()
-	var err error
-
-	//从文件中解析映射规则
-	if mappingFile != "" {
-		mappingFileContent, err := ioutil.ReadFile(mappingFile)
-		if err != nil {
-			fmt.Printf("读取映射文件失败:%v\n", err)
-			os.Exit(1)
-		}
-		for _, mappingStr := range strings.Split(string(mappingFileContent), "\n") {
-			mappingStr = strings.TrimSpace(mappingStr)
-			if mappingStr == "" {
-				continue
-			}
-			if err := addMapping(mappingStr); err != nil {
-				fmt.Printf("映射文件格式错误: %v\n", err)
-				os.Exit(1)
-			}
-		}
-	}
-	//从参数中解析映射规则
-	if len(mapping) > 0 {
-		for _, mappingStr := range mapping {
-			if err := addMapping(mappingStr); err != nil {
+	if len(os.Args) > 1 {
+		var command func([]string) error
+		switch os.Args[1] {
+		case "struct2table":
+			command = runStruct2Table
+		case "diff":
+			command = runDiff
+		}
+		if command != nil {
+			if err := command(os.Args[2:]); err != nil {
 				fmt.Println(err)
 				os.Exit(1)
 			}
+			return
 		}
 	}
+	flag.Parse()
+	if err := run(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	opts.Tables = flag.Args()
+	if err := loadConfig(configPath); err != nil {
+		return err
+	}
+	//未指定端口时使用对应数据库的默认端口
+	if !flag.CommandLine.Changed("db_port") {
+		opts.DBPort = 0
+	}
+
+	g, err := generator.New(opts)
+	if err != nil {
+		return err
+	}
+	defer g.Close()
 
 	if query != "" {
 		tableName, originName, err := parseQuery(query)
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		displayTable := ""
 		if tableName != "" {
 			displayTable = tableName + "."
 		}
-		fmt.Println(query, "=>", displayTable+toGoName(originName, tableName))
-		return
+		fmt.Println(query, "=>", displayTable+g.GoName(originName, tableName))
+		return nil
 	}
 
+	//--check和--diff不写入任何文件,也就无法保存快照
+	if dumpSchema != "" && (check || showDiff) {
+		return fmt.Errorf("--dump-schema不能与--check或--diff同时使用")
+	}
 	if _, statErr := os.Stat(output); statErr != nil {
 		if os.IsNotExist(statErr) {
-			fmt.Printf("错误的输入路径:%v", output)
-			os.Exit(1)
+			return fmt.Errorf("错误的输入路径:%v", output)
 		}
 	}
-	if dbName == "" {
-		fmt.Printf("请输入数据库名称")
-		os.Exit(1)
-	}
-	db, err = sqlx.Open("mysql", fmt.Sprintf("%s:%s@tcp(%s:%d)/information_schema?parseTime=true", dbUser, dbPwd, dbHost, dbPort))
-	if err != nil {
-		fmt.Printf("连接数据库失败:%v", err)
-		os.Exit(1)
-	}
-	defer db.Close()
-
-	tableSchemas, err := GetTables(flag.Args())
+	files, err := g.Generate(context.Background())
 	if err != nil {
-		fmt.Printf("读取数据库表失败:%v", err)
-		os.Exit(1)
-	}
-	for _, tableSchema := range tableSchemas {
-		//当表名不包含指定前缀时跳过
-		if tablePrefix != "" && skipIfNoPrefix && !strings.Contains(tableSchema.TableName, tablePrefix) {
-			continue
-		}
-		table, err := GetTable(tableSchema)
-		if err != nil {
-			fmt.Printf("读取表%v失败:%v\n", tableSchema.TableName, err)
-			os.Exit(1)
-		}
-		content, err := format.Source([]byte(toStruct(table)))
-		if err != nil {
-			fmt.Println("格式化失败:", err)
-			os.Exit(1)
-		}
-		if err := ioutil.WriteFile(filepath.Join(output, table.Name+".go"), content, 0666); err != nil {
-			fmt.Println("保存文件失败:", err)
-			os.Exit(1)
-		}
-	}
-
-}
-
-//toGoName 参考 github.com/jinzhu/gorm 的 ToDBName
-func toGoName(dbName string, tableName string) string {
-	if m, ok := dbMapping[tableName]; ok {
-		if mapping, goNameOK := m[dbName]; goNameOK {
-			return mapping.FieldName
-		}
+		return err
 	}
-	if m, ok := dbMapping["global"]; ok {
-		if mapping, goNameOK := m[dbName]; goNameOK {
-			return mapping.FieldName
+	if warnings := g.Warnings(); len(warnings) > 0 {
+		printWarnings(warnings)
+		if strict {
+			return fmt.Errorf("共有%d个警告,已停止生成", len(warnings))
 		}
 	}
-	if len(dbName) == 1 {
-		return strings.ToUpper(dbName)
-	}
-	var value string
-	for i, v := range dbName {
-		if (v >= 'A' && v <= 'Z') || (v >= 'a' && v <= 'z') {
-			value = dbName[i:]
-			break
+	if check || showDiff {
+		changed, err := compareFiles(os.Stdout, files, output, showDiff)
+		if err != nil {
+			return err
 		}
-	}
-	value = commonInitialismsReplacer.Replace(value)
-	buf := bytes.NewBufferString("")
-	for i, v := range value[:len(value)-1] {
-		if i > 0 {
-			if v == '_' || v == '-' {
-				continue
+		//只生成指定的表时,其他表的model不是多余的
+		var stale []string
+		if len(opts.Tables) == 0 {
+			if stale, err = compareStaleFiles(os.Stdout, files, output, showDiff); err != nil {
+				return err
 			}
-			if value[i-1] == '_' {
-				buf.WriteRune(unicode.ToUpper(v))
-			} else {
-				buf.WriteRune(v)
-			}
-		} else {
-			buf.WriteRune(unicode.ToUpper(v))
-		}
-	}
-	buf.WriteByte(value[len(value)-1])
-	return buf.String()
-}
-
-//GetTables 获取所有表
-func GetTables(args []string) ([]TableSchema, error) {
-	tables := make([]TableSchema, 0, 32)
-	whereTables := ""
-	if len(args) > 0 {
-		for i := range args {
-			args[i] = "'" + args[i] + "'"
-		}
-		whereTables = " AND TABLE_NAME IN (" + strings.Join(args, ",") + ")"
-	}
-	sqlStr := fmt.Sprintf("SELECT TABLE_CATALOG,TABLE_SCHEMA,TABLE_NAME,TABLE_TYPE,ENGINE,`VERSION`,ROW_FORMAT,TABLE_ROWS,AVG_ROW_LENGTH,DATA_LENGTH,MAX_DATA_LENGTH,INDEX_LENGTH,DATA_FREE,`AUTO_INCREMENT`,CREATE_TIME,UPDATE_TIME,CHECK_TIME,TABLE_COLLATION,CHECKSUM,CREATE_OPTIONS,TABLE_COMMENT FROM information_schema.tables WHERE `TABLE_SCHEMA` = '%s'%s", dbName, whereTables)
-	rows, err := db.Queryx(sqlStr)
-
-	if err != nil {
-		return tables, err
-	}
-	var table TableSchema
-	for rows.Next() {
-		if err = rows.StructScan(&table); err != nil {
-			return tables, err
-		}
-		tables = append(tables, table)
-	}
-	return tables, nil
-}
-
-//GetTable 获取表
-func GetTable(tableSchema TableSchema) (Table, error) {
-	table := Table{
-		Fields: make([]Field, 0, 16),
-	}
-	table.Comment = tableSchema.TableComment.String
-	table.OriginName = tableSchema.TableName
-	table.Name = tableSchema.TableName
-	if tablePrefix != "" {
-		if strings.HasPrefix(tableSchema.TableName, tablePrefix) {
-			table.Name = tableSchema.TableName[len(tablePrefix):]
 		}
-	}
-	rows, err := db.Queryx(fmt.Sprintf("SELECT `TABLE_CATALOG`,`TABLE_SCHEMA`,`TABLE_NAME`,`COLUMN_NAME`,`ORDINAL_POSITION`,`COLUMN_DEFAULT`,`IS_NULLABLE`,`DATA_TYPE`,`CHARACTER_MAXIMUM_LENGTH`,`CHARACTER_OCTET_LENGTH`,`NUMERIC_PRECISION`,`NUMERIC_SCALE`,`DATETIME_PRECISION`,`CHARACTER_SET_NAME`,`COLLATION_NAME`,`COLUMN_TYPE`,`COLUMN_KEY`,`EXTRA`,`PRIVILEGES`,`COLUMN_COMMENT`,`GENERATION_EXPRESSION` FROM information_schema.columns WHERE `TABLE_SCHEMA` = '%s' AND `TABLE_NAME` = '%s'", dbName, tableSchema.TableName))
-	if err != nil {
-		return table, err
-	}
-	var col ColumnSchema
-	for rows.Next() {
-		if err = rows.StructScan(&col); err != nil {
-			return table, err
+		if !check {
+			return nil
 		}
-		field := ParseField(col)
-		if field.Type == "time.Time" {
-			table.HasTime = true
+		var messages []string
+		if len(changed) > 0 {
+			messages = append(messages, "以下文件与数据库结构不一致,请重新生成:\n"+strings.Join(changed, "\n"))
 		}
-		table.Fields = append(table.Fields, field)
-	}
-	return table, nil
-}
-
-const (
-	tableTpl = `
-package %s
-
-%s
-
-//%s %s
-type %s struct {
-%s
-}
-
-//TableName %s
-func (t %s) TableName() string {
-	return "%s"
-}`
-)
-
-//toStruct 将表转换为struct字符串
-func toStruct(table Table) string {
-	buf := bytes.NewBufferString("")
-	var hasNullType = false
-	var hasExtNullType = false
-	for _, field := range table.Fields {
-		if field.IsNullType {
-			hasNullType = true
-		}
-		if field.IsExtNullType {
-			hasExtNullType = true
-		}
-		if field.Comment != "" {
-			buf.WriteString("//" + toGoName(field.Name, table.Name) + " " + field.Comment + "\n")
-		}
-		buf.WriteString(toGoName(field.Name, table.Name) + "\t" + field.Type)
-		tags := make([]string, 0)
-		if tagJSON {
-			tags = append(tags, `json:"`+field.Name+`"`)
+		if len(stale) > 0 {
+			messages = append(messages, "以下文件对应的表已不存在,请删除:\n"+strings.Join(stale, "\n"))
 		}
-		if tagSQLX {
-			tags = append(tags, `db:"`+field.Name+`"`)
-		}
-		if tagGORM {
-			gormTags := []string{"column:" + field.Name}
-			if tagGORMType {
-				if strings.Contains(field.OriginType, ")") {
-					gormTags = append(gormTags, "type:"+field.OriginType[:strings.Index(field.OriginType, ")")+1])
-				} else {
-					gormTags = append(gormTags, "type:"+field.OriginType)
-				}
-			}
-			if !field.EnableNull {
-				gormTags = append(gormTags, "not null")
-			}
-			if field.IsPrimaryKey {
-				gormTags = append(gormTags, "primary_key")
-			}
-			if field.IsAutoIncrement {
-				gormTags = append(gormTags, "AUTO_INCREMENT")
-			}
-			tags = append(tags, fmt.Sprintf(`gorm:"%s"`, strings.Join(gormTags, ";")))
-		}
-		if tagXORM {
-			xormTags := []string{"'" + field.Name + "'"}
-			if tagXORMType {
-				if strings.Contains(field.OriginType, ")") {
-					xormTags = append(xormTags, field.OriginType[:strings.Index(field.OriginType, ")")+1])
-				} else {
-					xormTags = append(xormTags, field.OriginType)
-				}
-			}
-			tags = append(tags, fmt.Sprintf(`xorm:"%s"`, strings.Join(xormTags, " ")))
-		}
-		if len(tags) > 0 {
-			tag := strings.Join(tags, " ")
-			buf.WriteString(" `" + tag + "`")
-		}
-		buf.WriteRune('\n')
-	}
-	tableGoName := toGoName(table.Name, table.Name)
-	importString := "\n"
-	imports := make([]string, 0, 2)
-	if table.HasTime {
-		imports = append(imports, `"time"`)
-	}
-	if hasNullType {
-		imports = append(imports, `"database/sql"`)
-	}
-	if hasExtNullType {
-		imports = append(imports, `nulltype "github.com/mattn/go-nulltype"`)
-	}
-	if len(imports) > 0 {
-		importString = fmt.Sprintf(`
-		import (
-			%s
-		)
-		`, strings.Join(imports, "\n"))
-	}
-	comment := table.Name
-	if table.Comment != "" {
-		comment = table.Comment
-	}
-	return fmt.Sprintf(tableTpl, packageName, importString, tableGoName, comment, tableGoName, buf.String(), tablePrefix+table.Name, tableGoName, tablePrefix+table.Name)
-}
-
-//ParseField 解析字段
-func ParseField(col ColumnSchema) Field {
-	var field Field
-	if strings.Contains(col.ColumnType, "unsigned") {
-		field.IsUnsigned = true
-	}
-	if col.IsNullAble == "YES" {
-		field.EnableNull = true
-	}
-	if strings.Contains(col.ColumnKey.String, "PRI") {
-		field.IsPrimaryKey = true
-	}
-	if strings.Contains(col.Extra.String, "auto_increment") {
-		field.IsAutoIncrement = true
-	}
-	field.Name = col.ColumnName
-	field.Type, field.IsNullType, field.IsExtNullType = goType(col.DataType, field.EnableNull)
-	if field.IsUnsigned && useUnsigned && strings.Contains(strings.ToLower(field.Type), "int") && !useInt64 {
-		field.Type = "u" + field.Type
-	}
-	// 如果映射中有设定数据类型则从映射中获取数据类型: {{{1
-	if m, ok := dbMapping["global"]; ok {
-		if mapping, ok := m[field.Name]; ok && mapping.FieldType != "" {
-			field.Type = mapping.FieldType
+		if len(messages) > 0 {
+			return fmt.Errorf("%s", strings.Join(messages, "\n"))
 		}
+		return nil
 	}
-	if m, ok := dbMapping[col.TableName]; ok {
-		if mapping, ok := m[field.Name]; ok && mapping.FieldType != "" {
-			field.Type = mapping.FieldType
+	for _, file := range files {
+		if err := ioutil.WriteFile(filepath.Join(output, file.Name), file.Content, 0666); err != nil {
+			return fmt.Errorf("保存文件失败:%v", err)
 		}
 	}
-	//无视映射规则中的大小写
-	switch strings.ToUpper(field.Type) {
-	case "SQL.NULLINT64":
-		field.IsNullType = true
-		field.Type = "sql.NullInt64"
-	case "SQL.NULLSTRING":
-		field.IsNullType = true
-		field.Type = "sql.NullString"
-	case "SQL.NULLBOOL":
-		field.IsNullType = true
-		field.Type = "sql.NullBool"
-	case "SQL.NULLFLOAT64":
-		field.IsNullType = true
-		field.Type = "sql.NullFloat64"
-	case "NULLTYPE.NULLINT64":
-		field.IsExtNullType = true
-		field.Type = "nulltype.NullInt64"
-	case "NULLTYPE.NULLSTRING":
-		field.IsExtNullType = true
-		field.Type = "nulltype.NullString"
-	case "NULLTYPE.NULLBOOL":
-		field.IsExtNullType = true
-		field.Type = "nulltype.NullBool"
-	case "NULLTYPE.NULLFLOAT64":
-		field.IsExtNullType = true
-		field.Type = "nulltype.NullFloat64"
-	case "NULLTYPE.NULLTIME":
-		field.IsExtNullType = true
-		field.Type = "nulltype.NullTime"
-	}
-	// }}}
-
-	field.Comment = col.ColumnComment.String
-	field.Default = col.ColumnDefault.String
-	field.OriginType = col.ColumnType
-	return field
-}
-
-func goType(dbType string, isNullAble bool) (goType string, isNullType bool, IsExtNullType bool) {
-	switch dbType {
-	case "tinyint":
-		if useInt64 {
-			if nullType && isNullAble {
-				if extNullType {
-					return "nulltype.NullInt64", false, true
-				}
-				return "sql.NullInt64", true, false
-			}
-			return "int64", false, false
-		}
-		if nullType && isNullAble {
-			if extNullType {
-				return "nulltype.NullInt64", false, true
-			}
-			return "sql.NullInt64", true, false
-		}
-		return "int8", false, false
-	case "smallint":
-		fallthrough
-	case "mediumint":
-		fallthrough
-	case "integer":
-		fallthrough
-	case "int":
-		if useInt64 {
-			if nullType && isNullAble {
-				if extNullType {
-					return "nulltype.NullInt64", false, true
-				}
-				return "sql.NullInt64", true, false
-			}
-			return "int64", false, false
-		}
-		if nullType && isNullAble {
-			if extNullType {
-				return "nulltype.NullInt64", false, true
-			}
-			return "sql.NullInt64", true, false
-		}
-		return "int", false, false
-	case "bigint":
-		if nullType && isNullAble {
-			if extNullType {
-				return "nulltype.NullInt64", false, true
-			}
-			return "sql.NullInt64", true, false
-		}
-		return "int64", false, false
-	case "float":
-		fallthrough
-	case "double":
-		fallthrough
-	case "decimal":
-		fallthrough
-	case "numeric":
-		if nullType && isNullAble {
-			if extNullType {
-				return "nulltype.NullFloat64", false, true
-			}
-			return "sql.NullFloat64", true, false
-		}
-		return "float64", false, false
-	case "bool":
-		if nullType && isNullAble {
-			if extNullType {
-				return "nulltype.NullBool", false, true
-			}
-			return "sql.NullBool", true, false
-		}
-		return "bool", false, false
-	case "char":
-		fallthrough
-	case "varchar":
-		fallthrough
-	case "tinytext":
-		fallthrough
-	case "text":
-		fallthrough
-	case "mediumtext":
-		fallthrough
-	case "longtext":
-		if nullType && isNullAble {
-			if extNullType {
-				return "nulltype.NullString", false, true
-			}
-			return "sql.NullString", true, false
-		}
-		return "string", false, false
-	case "date":
-		fallthrough
-	case "datetime":
-		fallthrough
-	case "time":
-		fallthrough
-	case "timestamp":
-		if nullType && extNullType && isNullAble {
-			return "nulltype.NullTime", false, true
-		}
-		return "time.Time", false, false
-	case "enum":
-		if nullType && isNullAble {
-			if extNullType {
-				return "nulltype.NullString", false, true
-			}
-			return "sql.NullString", true, false
-		}
-		return "string", false, false
-	case "json":
-		if extNullType {
-			return "nulltype.NullString", false, true
-		}
-		return "sql.NullString", true, false
-	default:
-		panic("未知类型:" + dbType)
+	if dumpSchema != "" {
+		return g.DumpSchema(context.Background(), dumpSchema)
 	}
+	return nil
 }
 
-//addMapping 增加映射
-func addMapping(m string) error {
-	if strings.Count(m, ":") == 0 {
-		return fmt.Errorf("映射格式错误: [%s]", m)
-	}
-	index := strings.Index(m, ":")
-	if index == 0 || index >= len(m)-2 {
-		return fmt.Errorf("映射格式错误: [%s]", m)
-	}
-	origin := m[0:index]
-	dest := m[index+1:]
-	var originName string
-	tableName := "global"
-	if strings.Contains(origin, ".") {
-		m2 := strings.Split(origin, ".")
-		if len(m2) != 2 {
-			return fmt.Errorf("映射格式错误: [%s]", m)
-		}
-		tableName, originName = m2[0], m2[1]
-	} else {
-		originName = origin
-	}
-	mapping := Mapping{}
-	if strings.Contains(dest, ",") {
-		m3 := strings.Split(dest, ",")
-		mapping.FieldName = m3[0]
-		for i := 1; i < len(m3); i++ {
-			attr := strings.Split(m3[i], ":")
-			if attr[0] == "type" {
-				mapping.FieldType = attr[1]
-			}
-		}
-	} else {
-		mapping.FieldName = dest
-	}
-
-	if _, ok := dbMapping[tableName]; !ok {
-		dbMapping[tableName] = make(map[string]Mapping)
+//printWarnings 将生成过程中的警告汇总输出到标准错误
+func printWarnings(warnings []string) {
+	fmt.Fprintf(os.Stderr, "共有%d个警告:\n", len(warnings))
+	for _, warning := range warnings {
+		fmt.Fprintln(os.Stderr, "  "+warning)
 	}
-	dbMapping[tableName][originName] = mapping
-	return nil
 }
 
 func parseQuery(query string) (tableName, fieldName string, err error) {
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/jiazhoulvke/table2struct/generator"
+	flag "github.com/spf13/pflag"
+)
+
+//runStruct2Table 根据go源文件中的struct生成建表语句
+func runStruct2Table(args []string) error {
+	flags := flag.NewFlagSet("struct2table", flag.ContinueOnError)
+	var outputFile string
+	var strict bool
+	flags.StringVar(&outputFile, "output", "", "输出的sql文件,默认输出到标准输出")
+	flags.BoolVar(&strict, "strict", false, "有警告(如无法识别的类型)时以非0状态退出")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: table2struct struct2table [flags] <go文件或目录>...")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return nil
+		}
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return fmt.Errorf("请指定go源文件或目录")
+	}
+	schema, warnings, err := generator.ParseStructFiles(flags.Args())
+	if err != nil {
+		return err
+	}
+	if len(warnings) > 0 {
+		printWarnings(warnings)
+		if strict {
+			return fmt.Errorf("共有%d个警告,已停止生成", len(warnings))
+		}
+	}
+	var buf bytes.Buffer
+	for i, table := range schema.Tables {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		buf.WriteString(generator.CreateTableSQL(table))
+	}
+	if outputFile == "" {
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	if err := ioutil.WriteFile(outputFile, buf.Bytes(), 0666); err != nil {
+		return fmt.Errorf("保存文件失败:%v", err)
+	}
+	return nil
+}